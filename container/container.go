@@ -1,9 +1,12 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 )
 
 type (
@@ -19,15 +22,19 @@ type (
 		Type reflect.Type
 	}
 
+	// Lifetime controls how long a resolved instance is reused.
+	Lifetime int
+
 	// providerEntry represents a registration record for a service provider.
-	// It stores the factory function, lifecycle (singleton or transient),
+	// It stores the factory function, lifetime (singleton, transient or scoped),
 	// any initialized instance, initialization hook, and type metadata.
 	providerEntry struct {
 		// factory constructs a new instance of the provider.
-		factory func(...interface{}) interface{}
+		// res carries the active Scope and the chain of providers being constructed.
+		factory func(res resolution) (interface{}, error)
 
-		// singleton indicates whether to reuse the same instance across resolves.
-		singleton bool
+		// lifetime decides whether instances are shared per container, per scope, or never.
+		lifetime Lifetime
 
 		// instance holds the created singleton instance after first resolution.
 		instance interface{}
@@ -38,31 +45,107 @@ type (
 		// initHook is an optional callback that runs once after creating the instance.
 		initHook func(interface{})
 
+		// closeHook, if non-nil, releases an instance on shutdown or scope disposal.
+		// Without it, instances implementing io.Closer are closed.
+		closeHook func(interface{}) error
+
+		// startHook and stopHook are lifecycle callbacks run by Start and Stop.
+		startHook func(context.Context, interface{}) error
+		stopHook  func(context.Context, interface{}) error
+
+		// healthCheck, if non-nil, checks the health of the instance for readiness checks.
+		healthCheck func(context.Context, interface{}) error
+
 		// interfaceType, if non-nil, registers this provider under a Go interface type.
 		interfaceType reflect.Type
 
 		// providerType is the concrete type returned by the factory or provided directly.
 		providerType reflect.Type
 
+		// static reports whether the provider is an instance rather than a factory,
+		// so that every resolve yields that same instance.
+		static bool
+
 		// deps holds the dependencies of the provider, if any.
+		// Dependencies without a Key are resolved by type.
 		deps []DependencyConfig
 	}
 
-	// container is the internal DI container managing provider registrations
+	// Container is a DI container managing provider registrations
 	// and type-to-key mappings. It is safe for concurrent use.
-	container struct {
+	// Create one with New; the package-level functions operate on Default().
+	Container struct {
 		mu           sync.RWMutex              // protects all maps below
 		providers    map[string]*providerEntry // key -> providerEntry
 		interfaceMap map[reflect.Type]string   // interface type -> key
 		typeMap      map[reflect.Type][]string // concrete type -> key
+		parent       *Container                // fallback for keys and types not registered here
+		created      []createdInstance         // singletons in construction order, closed in reverse
+		started      []createdInstance         // singletons started by Start, stopped in reverse
+		observer     Observer                  // receives resolve measurements; nil inherits the parent's
 	}
 )
 
+const (
+	// Singleton reuses one instance for the whole lifetime of the container.
+	Singleton Lifetime = iota
+
+	// Transient creates a new instance on each resolve.
+	Transient
+
+	// Scoped reuses one instance per Scope, e.g. per HTTP request.
+	Scoped
+)
+
+// String returns the lower-case name of the lifetime.
+func (l Lifetime) String() string {
+
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	default:
+		return fmt.Sprintf("Lifetime(%d)", int(l))
+	}
+}
+
 // globalContainer is the shared, package-level DI container instance.
-var globalContainer = &container{
-	providers:    make(map[string]*providerEntry),
-	interfaceMap: make(map[reflect.Type]string),
-	typeMap:      make(map[reflect.Type][]string),
+var globalContainer = New()
+
+// New creates an empty, independent Container.
+func New() *Container {
+
+	return &Container{
+		providers:    make(map[string]*providerEntry),
+		interfaceMap: make(map[reflect.Type]string),
+		typeMap:      make(map[reflect.Type][]string),
+	}
+}
+
+// NewChild creates an empty Container that falls back to c for every key or type
+// it does not register itself. Registrations in the child override the parent's
+// without affecting it; singletons of the parent stay shared with the child.
+func (c *Container) NewChild() *Container {
+
+	child := New()
+	child.parent = c
+
+	return child
+}
+
+// Parent returns the container c falls back to, or nil for a root container.
+func (c *Container) Parent() *Container {
+
+	return c.parent
+}
+
+// Default returns the shared, package-level Container used by Register, Resolve and friends.
+func Default() *Container {
+
+	return globalContainer
 }
 
 // RegisterOption configures a providerEntry (e.g., scope, init hook, interface binding).
@@ -71,13 +154,21 @@ type RegisterOption func(*providerEntry)
 // WithSingleton marks the provider as a singleton (default behavior).
 func WithSingleton() RegisterOption {
 
-	return func(pe *providerEntry) { pe.singleton = true }
+	return func(pe *providerEntry) { pe.lifetime = Singleton }
 }
 
 // WithTransient marks the provider as transient, creating a new instance on each resolve.
 func WithTransient() RegisterOption {
 
-	return func(pe *providerEntry) { pe.singleton = false }
+	return func(pe *providerEntry) { pe.lifetime = Transient }
+}
+
+// WithScoped marks the provider as scoped, sharing one instance per Scope.
+// Scoped providers can only be resolved through a context carrying a Scope
+// (see NewScope and ResolveContext); Solanum opens one scope per HTTP request.
+func WithScoped() RegisterOption {
+
+	return func(pe *providerEntry) { pe.lifetime = Scoped }
 }
 
 // WithInit sets a hook function to be invoked once after the instance is created.
@@ -86,6 +177,29 @@ func WithInit(hook func(interface{})) RegisterOption {
 	return func(pe *providerEntry) { pe.initHook = hook }
 }
 
+// WithClose sets a function that releases the instance when the container shuts down
+// (singletons) or when its scope is disposed (scoped providers). It takes precedence
+// over the automatic io.Closer detection.
+func WithClose(closeFn func(any) error) RegisterOption {
+
+	return func(pe *providerEntry) { pe.closeHook = closeFn }
+}
+
+// WithOnStart sets a hook that Start runs on the singleton instance, after the start
+// hooks of its dependencies. Use it to warm caches or start background consumers.
+// Providers with lifecycle hooks are constructed eagerly by Start.
+func WithOnStart(hook func(ctx context.Context, inst any) error) RegisterOption {
+
+	return func(pe *providerEntry) { pe.startHook = hook }
+}
+
+// WithOnStop sets a hook that Stop runs on the singleton instance, before the stop
+// hooks of its dependencies. Use it to stop consumers or flush buffers.
+func WithOnStop(hook func(ctx context.Context, inst any) error) RegisterOption {
+
+	return func(pe *providerEntry) { pe.stopHook = hook }
+}
+
 // WithDep lets you specify a key and type for the dependency.
 // declares dep, before calling your provider func,
 // the container should Resolve(key) and inject it as the T-typed argument.
@@ -113,15 +227,23 @@ func As(ifacePtr interface{}) RegisterOption {
 	}
 }
 
+// Register adds a new provider under the given key to the default container.
+// See (*Container).Register.
+func Register(key string, provider interface{}, opts ...RegisterOption) {
+
+	globalContainer.Register(key, provider, opts...)
+}
+
 // Register adds a new provider under the given key. The provider can be either:
 //   - a factory function: func(...) (T, error)
 //   - a concrete value: T
 //
-// Options control scope, init hooks, and interface binding.
-func Register(key string, provider interface{}, opts ...RegisterOption) {
+// Options control lifetime, init hooks, and interface binding.
+// Dependencies of a factory are resolved from this container.
+func (c *Container) Register(key string, provider interface{}, opts ...RegisterOption) {
 
-	// Default to singleton scope
-	pe := &providerEntry{singleton: true}
+	// Default to singleton lifetime
+	pe := &providerEntry{lifetime: Singleton}
 
 	for _, opt := range opts {
 		opt(pe)
@@ -144,10 +266,13 @@ func Register(key string, provider interface{}, opts ...RegisterOption) {
 		}
 	}
 
+	// Keep the effective dependencies for validation and cycle detection
+	pe.deps = deps
+
 	// If provider is a function, wrap it to perform nested dependency resolution
 	if pt.Kind() == reflect.Func {
 
-		pe.factory = func(...interface{}) interface{} {
+		pe.factory = func(res resolution) (interface{}, error) {
 
 			// Resolve each function parameter by type
 			args := make([]reflect.Value, len(deps))
@@ -162,145 +287,302 @@ func Register(key string, provider interface{}, opts ...RegisterOption) {
 
 					if d.Type.Kind() == reflect.Interface {
 
-						inst, err = ResolveByType(d.Key, d.Type)
+						inst, err = c.resolveByType(d.Key, d.Type, res)
 					} else {
 
-						inst, err = Resolve(d.Key)
+						inst, err = c.resolve(d.Key, res)
 					}
 				} else {
 
 					// inference dependency from type only
-					inst, err = globalContainer.resolveByReflectType(d.Type)
+					inst, err = c.resolveByReflectType(d.Type, res)
 				}
 
 				if err != nil {
 
-					panic(fmt.Errorf("failed to resolve %q [%v]: %w", d.Key, d.Type, err))
+					// A cycle already names the whole chain; don't bury it in wrapping
+					var cycle *CycleError
+					if errors.As(err, &cycle) {
+
+						return nil, err
+					}
+
+					return nil, fmt.Errorf("failed to resolve %q [%v]: %w", d.Key, d.Type, err)
 				}
 
-				args[i] = reflect.ValueOf(inst)
+				if inst == nil {
+
+					args[i] = reflect.Zero(d.Type)
+				} else {
+
+					args[i] = reflect.ValueOf(inst)
+				}
 			}
 
 			// call original provider with resolved args
 			out := pv.Call(args)
 			if len(out) == 2 && !out[1].IsNil() {
 
-				panic(out[1].Interface())
+				if err, ok := out[1].Interface().(error); ok {
+
+					return nil, err
+				}
+
+				return nil, fmt.Errorf("%v", out[1].Interface())
 			}
 
-			return out[0].Interface()
+			return out[0].Interface(), nil
 		}
 		// Provider returns the first result type
 		pe.providerType = pt.Out(0)
 	} else {
 
 		// Static instance provider
-		pe.factory = func(_ ...interface{}) interface{} {
-			return provider
+		pe.factory = func(_ resolution) (interface{}, error) {
+			return provider, nil
 		}
 		pe.providerType = reflect.TypeOf(provider)
+		pe.static = true
 	}
 
-	// Apply all registration options
-	for _, o := range opts {
-
-		o(pe)
-	}
-
-	// Store in global container with thread safety
-	globalContainer.mu.Lock()
-	globalContainer.providers[key] = pe
+	// Store in the container with thread safety
+	c.mu.Lock()
+	c.unregisterLocked(key)
+	c.providers[key] = pe
 
 	if pe.interfaceType != nil {
 
-		globalContainer.interfaceMap[pe.interfaceType] = key
+		c.interfaceMap[pe.interfaceType] = key
 	}
 
 	// Store the key under the concrete type
-	globalContainer.typeMap[pe.providerType] = append(globalContainer.typeMap[pe.providerType], key)
-	globalContainer.mu.Unlock()
+	c.typeMap[pe.providerType] = append(c.typeMap[pe.providerType], key)
+	c.mu.Unlock()
 }
 
-// resolveByReflectType finds a registration key by interface or concrete type,
-// then calls Resolve(key). Returns an error if no matching provider found.
-func (c *container) resolveByReflectType(t reflect.Type) (interface{}, error) {
+// unregisterLocked drops any previous registration of key, so that registering
+// the same key twice replaces the provider instead of making its type ambiguous.
+// The caller must hold c.mu for writing.
+func (c *Container) unregisterLocked(key string) {
 
-	c.mu.RLock()
+	old, exists := c.providers[key]
+	if !exists {
 
-	// If the type is an interface, check the interfaceMap first
-	if key, ok := c.interfaceMap[t]; ok {
+		return
+	}
 
-		c.mu.RUnlock()
-		return Resolve(key)
+	if old.interfaceType != nil && c.interfaceMap[old.interfaceType] == key {
+
+		delete(c.interfaceMap, old.interfaceType)
 	}
 
-	// If the type is concrete, check the typeMap
-	if keys, ok := c.typeMap[t]; ok {
+	keys := c.typeMap[old.providerType]
+	for i, k := range keys {
 
-		if len(keys) == 1 {
+		if k == key {
 
-			c.mu.RUnlock()
-			return Resolve(keys[0])
+			keys = append(keys[:i:i], keys[i+1:]...)
+			break
 		}
+	}
 
-		// If multiple keys exist for this type, return an error
-		// e.g., ResolveByType("key", t)
-		c.mu.RUnlock()
-		return nil, fmt.Errorf("ambiguous providers for type %v: keys=%v", t, keys)
+	if len(keys) == 0 {
+
+		delete(c.typeMap, old.providerType)
+	} else {
+
+		c.typeMap[old.providerType] = keys
 	}
 
-	c.mu.RUnlock()
-	return nil, fmt.Errorf("no provider for type %v", t)
+	delete(c.providers, key)
+}
+
+// resolveByReflectType finds a registration key by interface or concrete type,
+// then resolves that key. Returns an error if no matching provider found.
+func (c *Container) resolveByReflectType(t reflect.Type, res resolution) (interface{}, error) {
+
+	key, owner, err := c.lookupType(t)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return owner.resolve(key, res)
+}
+
+// Has reports whether a provider is registered under key in c or its ancestors.
+func (c *Container) Has(key string) bool {
+
+	pe, _ := c.lookup(key)
+	return pe != nil
+}
+
+// Resolve retrieves an instance registered under the given key from the default container.
+// See (*Container).Resolve.
+func Resolve(key string) (interface{}, error) {
+
+	return globalContainer.Resolve(key)
+}
+
+// ResolveContext is the scope-aware variant of Resolve on the default container.
+// See (*Container).ResolveContext.
+func ResolveContext(ctx context.Context, key string) (interface{}, error) {
+
+	return globalContainer.ResolveContext(ctx, key)
 }
 
 // Resolve retrieves an instance registered under the given key.
 //   - If the provider is a singleton and has already been constructed, it returns the stored instance.
 //   - Otherwise, it invokes the factory (outside any locks), stores the instance if singleton,
 //     and calls initHook exactly once (also outside the read-lock).
-func Resolve(key string) (interface{}, error) {
+//
+// Scoped providers cannot be resolved here; use ResolveContext with a scoped context instead.
+func (c *Container) Resolve(key string) (interface{}, error) {
+
+	return c.resolve(key, resolution{})
+}
+
+// ResolveContext behaves like Resolve, but resolves scoped providers against the
+// Scope carried by ctx (see NewScope). Within one scope, every resolve of a scoped
+// key returns the same instance.
+func (c *Container) ResolveContext(ctx context.Context, key string) (interface{}, error) {
+
+	return c.resolve(key, resolution{scope: ScopeFromContext(ctx)})
+}
+
+// resolve looks up key and returns an instance according to the provider's lifetime.
+// res.scope may be nil, in which case scoped providers fail to resolve.
+// Resolving a provider that is already being constructed in res yields a *CycleError.
+func (c *Container) resolve(key string, res resolution) (interface{}, error) {
 
-	globalContainer.mu.RLock()
-	pe, exists := globalContainer.providers[key]
+	c.mu.RLock()
+	pe, exists := c.providers[key]
 	if !exists {
 
-		globalContainer.mu.RUnlock()
+		c.mu.RUnlock()
+
+		// Not registered locally; defer to the parent container, if any
+		if c.parent != nil {
+
+			return c.parent.resolve(key, res)
+		}
+
 		return nil, fmt.Errorf("no provider registered for key %q", key)
 	}
+	c.mu.RUnlock()
+
+	o := c.observerOf()
+	if o == nil {
+
+		return c.resolveEntry(key, pe, res, nil)
+	}
+
+	start := time.Now()
+	inst, err := c.resolveEntry(key, pe, res, o)
+	o.ObserveResolve(key, time.Since(start), err)
+
+	return inst, err
+}
 
-	isSingleton := pe.singleton
+// resolveEntry returns an instance of the provider pe registered in c under key.
+// o, if non-nil, observes the construction of singletons.
+func (c *Container) resolveEntry(key string, pe *providerEntry, res resolution, o Observer) (interface{}, error) {
+
+	c.mu.RLock()
+	lifetime := pe.lifetime
 	existing := pe.instance
-	globalContainer.mu.RUnlock()
+	c.mu.RUnlock()
 
-	// Return existing instance if already created.
-	if isSingleton && existing != nil {
+	// Return existing singleton instance if already created.
+	if lifetime == Singleton && existing != nil {
 
 		return existing, nil
 	}
 
-	// Build the instance outside of any locks to avoid deadlocks.
-	inst := pe.factory()
-	if isSingleton {
+	next, err := res.enter(key, pe)
+	if err != nil {
+
+		return nil, err
+	}
+
+	var inst interface{}
+	switch lifetime {
+	case Singleton:
 
-		globalContainer.mu.Lock()
-		if pe.instance == nil {
+		// Build the instance outside of any locks to avoid deadlocks.
+		// Singletons outlive any scope, so they never see one.
+		next.scope = nil
+		start := time.Now()
+		if inst, err = pe.factory(next); err != nil {
+
+			return nil, err
+		}
+		elapsed := time.Since(start)
+
+		c.mu.Lock()
+		built := inst
+		constructed := pe.instance == nil
+		if constructed {
 
 			pe.instance = inst
+			c.created = append(c.created, createdInstance{key: key, pe: pe, inst: inst})
+		}
+
+		inst = pe.instance
+		c.mu.Unlock()
+
+		if constructed && o != nil {
+
+			o.ObserveSingleton(key, elapsed)
+		}
+
+		if !constructed {
+
+			// Another resolve built the singleton first; release ours
+			discard(pe, built, inst)
+		}
+
+	case Scoped:
+
+		if res.scope == nil {
+
+			return nil, fmt.Errorf("scoped provider %q resolved outside of a scope", key)
+		}
+
+		if cached, ok := res.scope.get(pe); ok {
+
+			return cached, nil
 		}
 
-		globalContainer.mu.Unlock()
+		if inst, err = pe.factory(next); err != nil {
+
+			return nil, err
+		}
+
+		if inst, err = res.scope.store(pe, key, inst); err != nil {
+
+			return nil, fmt.Errorf("cannot resolve scoped provider %q: %w", key, err)
+		}
+
+	default:
+
+		if inst, err = pe.factory(next); err != nil {
+
+			return nil, err
+		}
 	}
 
 	var doHook bool
 	if pe.initHook != nil {
 
-		globalContainer.mu.Lock()
+		c.mu.Lock()
 		if !pe.hookCalled {
 
 			pe.hookCalled = true
 			doHook = true
 		}
 
-		globalContainer.mu.Unlock()
+		c.mu.Unlock()
 
 		// Call the hook without holding any locks.
 		if doHook {
@@ -312,11 +594,36 @@ func Resolve(key string) (interface{}, error) {
 	return inst, nil
 }
 
+// ResolveByType resolves by key from the default container. See (*Container).ResolveByType.
+func ResolveByType(key string, ifaceType reflect.Type) (interface{}, error) {
+
+	return globalContainer.ResolveByType(key, ifaceType)
+}
+
+// ResolveByTypeContext resolves by key from the default container.
+// See (*Container).ResolveByTypeContext.
+func ResolveByTypeContext(ctx context.Context, key string, ifaceType reflect.Type) (interface{}, error) {
+
+	return globalContainer.ResolveByTypeContext(ctx, key, ifaceType)
+}
+
 // ResolveByType resolves by key and additionally asserts that the instance
 // implements the specified interface type. Passing nil for ifaceType skips the check.
-func ResolveByType(key string, ifaceType reflect.Type) (interface{}, error) {
+func (c *Container) ResolveByType(key string, ifaceType reflect.Type) (interface{}, error) {
+
+	return c.resolveByType(key, ifaceType, resolution{})
+}
+
+// ResolveByTypeContext is the scope-aware variant of ResolveByType.
+func (c *Container) ResolveByTypeContext(ctx context.Context, key string, ifaceType reflect.Type) (interface{}, error) {
+
+	return c.resolveByType(key, ifaceType, resolution{scope: ScopeFromContext(ctx)})
+}
+
+// resolveByType resolves key within res and checks the instance against ifaceType.
+func (c *Container) resolveByType(key string, ifaceType reflect.Type, res resolution) (interface{}, error) {
 
-	inst, err := Resolve(key)
+	inst, err := c.resolve(key, res)
 	if err != nil {
 
 		return nil, err
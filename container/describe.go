@@ -0,0 +1,83 @@
+package container
+
+type (
+	// ProviderInfo describes a provider registration, e.g. for diagnostics.
+	ProviderInfo struct {
+		// Key the registration key
+		Key string `json:"key"`
+
+		// Lifetime the lifetime of instances: singleton, transient or scoped
+		Lifetime string `json:"lifetime"`
+
+		// Type the type the provider returns
+		Type string `json:"type"`
+
+		// Interface the interface the provider is bound to with As, if any
+		Interface string `json:"interface,omitempty"`
+
+		// Instantiated whether the singleton has been constructed; always false for other lifetimes
+		Instantiated bool `json:"instantiated"`
+
+		// Dependencies the providers the provider depends on, in argument order
+		Dependencies []DependencyInfo `json:"dependencies,omitempty"`
+	}
+
+	// DependencyInfo describes a dependency of a provider.
+	DependencyInfo struct {
+		// Key the key of the provider the dependency resolves to; empty if none is registered
+		Key string `json:"key"`
+
+		// Type the type of the dependency
+		Type string `json:"type"`
+	}
+)
+
+// Describe returns the providers registered in c, sorted by key, with the keys their
+// dependencies resolve to from c. Providers of parent containers are left to their own
+// container. Nothing is constructed.
+func (c *Container) Describe() []ProviderInfo {
+
+	keys := c.localKeys()
+	infos := make([]ProviderInfo, 0, len(keys))
+	for _, key := range keys {
+
+		c.mu.RLock()
+		pe, ok := c.providers[key]
+		if !ok {
+
+			c.mu.RUnlock()
+			continue
+		}
+
+		info := ProviderInfo{
+			Key:          key,
+			Lifetime:     pe.lifetime.String(),
+			Instantiated: pe.lifetime == Singleton && pe.instance != nil,
+		}
+		if pe.providerType != nil {
+
+			info.Type = pe.providerType.String()
+		}
+		if pe.interfaceType != nil {
+
+			info.Interface = pe.interfaceType.String()
+		}
+		deps := pe.deps
+		c.mu.RUnlock()
+
+		for _, d := range deps {
+
+			depKey, depPe, _ := c.dependencyOf(d)
+			if depPe == nil {
+
+				depKey = ""
+			}
+
+			info.Dependencies = append(info.Dependencies, DependencyInfo{Key: depKey, Type: d.Type.String()})
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
@@ -0,0 +1,263 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+
+	// CycleError reports a dependency cycle between providers.
+	// Chain lists the keys along the cycle, starting and ending with the same key.
+	CycleError struct {
+		Chain []string
+	}
+
+	// resolution carries per-call state through nested resolves.
+	resolution struct {
+		scope *Scope        // active scope, nil outside of one
+		path  []resolveStep // providers currently being constructed, outermost first
+	}
+
+	// resolveStep is one provider on a resolution path.
+	resolveStep struct {
+		key string
+		pe  *providerEntry
+	}
+
+	// graphNode is a provider in the dependency graph, along with the container owning it.
+	graphNode struct {
+		key   string
+		pe    *providerEntry
+		owner *Container
+	}
+)
+
+// Error implements the error interface, rendering the chain as "a -> b -> a".
+func (e *CycleError) Error() string {
+
+	return "dependency cycle detected :: " + strings.Join(e.Chain, " -> ")
+}
+
+// enter returns a copy of res with the provider appended to its path,
+// or a *CycleError if that provider is already being constructed.
+func (res resolution) enter(key string, pe *providerEntry) (resolution, error) {
+
+	for i, step := range res.path {
+
+		if step.pe == pe {
+
+			return res, newCycleError(res.path[i:], key)
+		}
+	}
+
+	// Copy on append so sibling resolutions never share a backing array
+	path := make([]resolveStep, len(res.path), len(res.path)+1)
+	copy(path, res.path)
+
+	return resolution{
+		scope: res.scope,
+		path:  append(path, resolveStep{key: key, pe: pe}),
+	}, nil
+}
+
+// newCycleError builds a CycleError from the looping part of a path and the key closing it.
+func newCycleError(loop []resolveStep, key string) *CycleError {
+
+	chain := make([]string, 0, len(loop)+1)
+	for _, step := range loop {
+
+		chain = append(chain, step.key)
+	}
+
+	return &CycleError{Chain: append(chain, key)}
+}
+
+// lookup finds the provider registered under key in c or its ancestors,
+// along with the container that owns it.
+func (c *Container) lookup(key string) (*providerEntry, *Container) {
+
+	for cur := c; cur != nil; cur = cur.parent {
+
+		cur.mu.RLock()
+		pe, ok := cur.providers[key]
+		cur.mu.RUnlock()
+
+		if ok {
+
+			return pe, cur
+		}
+	}
+
+	return nil, nil
+}
+
+// lookupType finds the key registered for an interface or concrete type in c
+// or its ancestors, along with the container that owns it.
+func (c *Container) lookupType(t reflect.Type) (string, *Container, error) {
+
+	for cur := c; cur != nil; cur = cur.parent {
+
+		cur.mu.RLock()
+
+		// If the type is an interface, check the interfaceMap first
+		if key, ok := cur.interfaceMap[t]; ok {
+
+			cur.mu.RUnlock()
+			return key, cur, nil
+		}
+
+		// If the type is concrete, check the typeMap
+		if keys, ok := cur.typeMap[t]; ok {
+
+			cur.mu.RUnlock()
+			if len(keys) == 1 {
+
+				return keys[0], cur, nil
+			}
+
+			// If multiple keys exist for this type, return an error
+			return "", nil, fmt.Errorf("ambiguous providers for type %v: keys=%v", t, keys)
+		}
+
+		cur.mu.RUnlock()
+	}
+
+	return "", nil, fmt.Errorf("no provider for type %v", t)
+}
+
+// dependencyOf finds the provider that a dependency declared by a provider of c points to.
+// It returns a nil entry if the dependency cannot be found; resolution reports that case.
+func (c *Container) dependencyOf(d DependencyConfig) (string, *providerEntry, *Container) {
+
+	if d.Key != "" {
+
+		pe, owner := c.lookup(d.Key)
+		return d.Key, pe, owner
+	}
+
+	key, owner, err := c.lookupType(d.Type)
+	if err != nil {
+
+		return "", nil, nil
+	}
+
+	pe, owner := owner.lookup(key)
+	return key, pe, owner
+}
+
+// CheckCycles walks the declared dependencies of every provider visible from c,
+// without constructing anything, and returns a *CycleError for the first cycle found.
+func (c *Container) CheckCycles() error {
+
+	_, err := c.dependencyOrder(c.keys())
+	return err
+}
+
+// dependencyOrder walks the declared dependencies of the providers registered under
+// keys, without constructing anything, and returns every provider reached with each
+// one listed after all of its dependencies. It returns a *CycleError on a cycle.
+func (c *Container) dependencyOrder(keys []string) ([]graphNode, error) {
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[*providerEntry]int)
+	var order []graphNode
+
+	var visit func(node graphNode, path []resolveStep) error
+	visit = func(node graphNode, path []resolveStep) error {
+
+		switch state[node.pe] {
+		case done:
+			return nil
+		case visiting:
+			for i, step := range path {
+
+				if step.pe == node.pe {
+
+					return newCycleError(path[i:], node.key)
+				}
+			}
+		}
+
+		state[node.pe] = visiting
+		path = append(path, resolveStep{key: node.key, pe: node.pe})
+
+		for _, d := range node.pe.deps {
+
+			depKey, depPe, depOwner := node.owner.dependencyOf(d)
+			if depPe == nil {
+
+				continue
+			}
+
+			if err := visit(graphNode{key: depKey, pe: depPe, owner: depOwner}, path); err != nil {
+
+				return err
+			}
+		}
+
+		state[node.pe] = done
+		order = append(order, node)
+		return nil
+	}
+
+	for _, key := range keys {
+
+		pe, owner := c.lookup(key)
+		if pe == nil {
+
+			continue
+		}
+
+		if err := visit(graphNode{key: key, pe: pe, owner: owner}, nil); err != nil {
+
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// localKeys returns the keys registered in c itself, in sorted order.
+func (c *Container) localKeys() []string {
+
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.providers))
+	for key := range c.providers {
+
+		keys = append(keys, key)
+	}
+	c.mu.RUnlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// keys returns every key visible from c, including its ancestors', in sorted order.
+func (c *Container) keys() []string {
+
+	seen := make(map[string]struct{})
+	for cur := c; cur != nil; cur = cur.parent {
+
+		cur.mu.RLock()
+		for key := range cur.providers {
+
+			seen[key] = struct{}{}
+		}
+		cur.mu.RUnlock()
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
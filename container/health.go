@@ -0,0 +1,90 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+type (
+	// HealthChecker is implemented by singletons able to report whether they work,
+	// e.g. a database pool pinging its server. Readiness checks call CheckHealth.
+	HealthChecker interface {
+		CheckHealth(ctx context.Context) error
+	}
+
+	// HealthCheck is the health check of a singleton provider.
+	HealthCheck struct {
+		// Key the registration key of the provider
+		Key string
+
+		// Check resolves the singleton and checks its health
+		Check func(ctx context.Context) error
+	}
+)
+
+// healthCheckerType is the reflect.Type of HealthChecker.
+var healthCheckerType = reflect.TypeOf((*HealthChecker)(nil)).Elem()
+
+// WithHealthCheck sets a function checking the health of the singleton instance, run
+// by readiness checks. It takes precedence over the instance's CheckHealth method.
+func WithHealthCheck(check func(ctx context.Context, inst any) error) RegisterOption {
+
+	return func(pe *providerEntry) { pe.healthCheck = check }
+}
+
+// HealthChecks returns the health checks of the singletons registered in c, sorted by
+// key: those registered with WithHealthCheck, and those implementing HealthChecker,
+// known from the provider's type or from the instance if it was already constructed.
+// Providers of parent containers are left to their own container.
+// Running a check constructs the singleton if needed; a construction failure fails it.
+func (c *Container) HealthChecks() []HealthCheck {
+
+	var checks []HealthCheck
+	for _, key := range c.localKeys() {
+
+		c.mu.RLock()
+		pe, ok := c.providers[key]
+		var checker bool
+		if ok {
+
+			_, instChecker := pe.instance.(HealthChecker)
+			checker = instChecker || (pe.providerType != nil && pe.providerType.Implements(healthCheckerType))
+		}
+		c.mu.RUnlock()
+
+		if !ok || pe.lifetime != Singleton || (pe.healthCheck == nil && !checker) {
+
+			continue
+		}
+
+		checks = append(checks, HealthCheck{Key: key, Check: c.healthCheck(key, pe)})
+	}
+
+	return checks
+}
+
+// healthCheck returns a function resolving the singleton under key and checking it.
+func (c *Container) healthCheck(key string, pe *providerEntry) func(ctx context.Context) error {
+
+	return func(ctx context.Context) error {
+
+		inst, err := c.resolve(key, resolution{})
+		if err != nil {
+
+			return fmt.Errorf("resolve %q :: %w", key, err)
+		}
+
+		if pe.healthCheck != nil {
+
+			return pe.healthCheck(ctx, inst)
+		}
+
+		if checker, ok := inst.(HealthChecker); ok {
+
+			return checker.CheckHealth(ctx)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// createdInstance records a singleton built by a container, for ordered shutdown.
+type createdInstance struct {
+	key  string         // registration key, used in error messages
+	pe   *providerEntry // provider that built the instance
+	inst interface{}    // the singleton instance
+}
+
+// closeInstance releases inst using the provider's close hook, or io.Closer if it has none.
+func closeInstance(pe *providerEntry, inst interface{}) error {
+
+	if pe.closeHook != nil {
+
+		return pe.closeHook(inst)
+	}
+
+	if closer, ok := inst.(io.Closer); ok {
+
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// discard releases inst, an instance built by pe that lost the race to be cached
+// against kept, e.g. when two requests construct the same singleton at once. Static
+// providers, and factories returning the same instance again, have nothing to release.
+func discard(pe *providerEntry, inst, kept interface{}) {
+
+	if pe.static {
+
+		return
+	}
+
+	if t := reflect.TypeOf(inst); t != nil && t.Comparable() && inst == kept {
+
+		return
+	}
+
+	// Nobody else holds inst, so a close error has no one to report to
+	_ = closeInstance(pe, inst)
+}
+
+// Shutdown closes the singletons of the default container. See (*Container).Shutdown.
+func Shutdown(ctx context.Context) error {
+
+	return globalContainer.Shutdown(ctx)
+}
+
+// Shutdown closes every singleton this container has constructed, in reverse
+// construction order, so that an instance is closed before the dependencies it was
+// built from. Each instance is released with its WithClose function, or Close if it
+// implements io.Closer. Closed singletons are forgotten and would be rebuilt by a
+// later resolve.
+//
+// All close errors are joined together. If ctx is done before every instance has
+// been closed, the remaining ones are skipped and ctx.Err() is included in the result.
+// Parent containers are not shut down by their children.
+func (c *Container) Shutdown(ctx context.Context) error {
+
+	c.mu.Lock()
+	created := c.created
+	c.created = nil
+	for _, ci := range created {
+
+		ci.pe.instance = nil
+		ci.pe.hookCalled = false
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+
+		if err := ctx.Err(); err != nil {
+
+			errs = append(errs, fmt.Errorf("shutdown interrupted before closing %q :: %w", created[i].key, err))
+			break
+		}
+
+		if err := closeInstance(created[i].pe, created[i].inst); err != nil {
+
+			errs = append(errs, fmt.Errorf("close %q :: %w", created[i].key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start runs the start hooks of the default container. See (*Container).Start.
+func Start(ctx context.Context) error {
+
+	return globalContainer.Start(ctx)
+}
+
+// Stop runs the stop hooks of the default container. See (*Container).Stop.
+func Stop(ctx context.Context) error {
+
+	return globalContainer.Stop(ctx)
+}
+
+// Start constructs every singleton registered in c with a WithOnStart or WithOnStop
+// hook and runs the start hooks in dependency order, so a provider starts only after
+// the providers it depends on. Providers of parent containers are left to their own
+// container. If a hook fails, the providers started so far are stopped again and the
+// error is returned.
+func (c *Container) Start(ctx context.Context) error {
+
+	order, err := c.dependencyOrder(c.localKeys())
+	if err != nil {
+
+		return err
+	}
+
+	for _, node := range order {
+
+		pe := node.pe
+		if node.owner != c || pe.lifetime != Singleton || (pe.startHook == nil && pe.stopHook == nil) {
+
+			continue
+		}
+
+		inst, err := c.resolve(node.key, resolution{})
+		if err == nil && pe.startHook != nil {
+
+			err = pe.startHook(ctx, inst)
+		}
+
+		if err != nil {
+
+			return errors.Join(fmt.Errorf("start %q :: %w", node.key, err), c.Stop(ctx))
+		}
+
+		c.mu.Lock()
+		c.started = append(c.started, createdInstance{key: node.key, pe: pe, inst: inst})
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Stop runs the stop hooks of the singletons started by Start, in reverse start order,
+// so a provider stops before the providers it depends on. All hooks run even if some
+// fail; their errors are joined together.
+func (c *Container) Stop(ctx context.Context) error {
+
+	c.mu.Lock()
+	started := c.started
+	c.started = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+
+		if started[i].pe.stopHook == nil {
+
+			continue
+		}
+
+		if err := started[i].pe.stopHook(ctx, started[i].inst); err != nil {
+
+			errs = append(errs, fmt.Errorf("stop %q :: %w", started[i].key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,44 @@
+package container
+
+import "time"
+
+// Observer receives measurements of a container's activity, e.g. to export metrics.
+// It is called on every resolve, so implementations must be fast and safe for
+// concurrent use.
+type Observer interface {
+	// ObserveResolve is called after key is resolved, with how long it took,
+	// dependencies included, and the error, if any.
+	ObserveResolve(key string, d time.Duration, err error)
+
+	// ObserveSingleton is called after the singleton under key is constructed,
+	// with how long its factory took.
+	ObserveSingleton(key string, d time.Duration)
+}
+
+// SetObserver makes o observe c and the children of c that have no observer of their own.
+// A nil o stops observing.
+func (c *Container) SetObserver(o Observer) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observer = o
+}
+
+// observerOf returns the observer of c or of its closest ancestor with one.
+func (c *Container) observerOf() Observer {
+
+	for cur := c; cur != nil; cur = cur.parent {
+
+		cur.mu.RLock()
+		o := cur.observer
+		cur.mu.RUnlock()
+
+		if o != nil {
+
+			return o
+		}
+	}
+
+	return nil
+}
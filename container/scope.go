@@ -0,0 +1,124 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type (
+
+	// Scope holds the instances of scoped providers for one unit of work,
+	// typically a single HTTP request. It is safe for concurrent use.
+	// Instances are tracked per provider, so one scope can serve several containers.
+	Scope struct {
+		mu        sync.Mutex                     // protects the fields below
+		instances map[*providerEntry]interface{} // provider -> scoped instance
+		order     []scopedInstance               // instances in creation order, disposed in reverse
+		disposed  bool                           // set once Dispose has run
+	}
+
+	// scopedInstance records an instance created within a Scope, for disposal.
+	scopedInstance struct {
+		key  string         // registration key, used in error messages
+		pe   *providerEntry // provider that built the instance
+		inst interface{}    // the scoped instance
+	}
+
+	// scopeContextKey is the context key under which the active Scope is stored.
+	scopeContextKey struct{}
+)
+
+// NewScope opens a new Scope and returns a derived context carrying it.
+// Resolving scoped providers through the returned context (see ResolveContext)
+// yields one shared instance per key until the scope is disposed.
+func NewScope(ctx context.Context) (context.Context, *Scope) {
+
+	scope := &Scope{
+		instances: make(map[*providerEntry]interface{}),
+	}
+
+	return context.WithValue(ctx, scopeContextKey{}, scope), scope
+}
+
+// ScopeFromContext returns the Scope carried by ctx, or nil if there is none.
+func ScopeFromContext(ctx context.Context) *Scope {
+
+	if ctx == nil {
+
+		return nil
+	}
+
+	scope, _ := ctx.Value(scopeContextKey{}).(*Scope)
+	return scope
+}
+
+// get returns the cached instance for the provider, if any.
+func (s *Scope) get(pe *providerEntry) (interface{}, bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.instances[pe]
+	return inst, ok
+}
+
+// store caches inst for the provider registered under key unless another instance
+// won the race, and returns the instance that ends up cached. An instance that is not
+// cached, because it lost the race or the scope is disposed, is released.
+// Storing into a disposed scope is an error.
+func (s *Scope) store(pe *providerEntry, key string, inst interface{}) (interface{}, error) {
+
+	s.mu.Lock()
+
+	if s.disposed {
+
+		s.mu.Unlock()
+		discard(pe, inst, nil)
+		return nil, errors.New("scope already disposed")
+	}
+
+	if existing, ok := s.instances[pe]; ok {
+
+		s.mu.Unlock()
+		discard(pe, inst, existing)
+		return existing, nil
+	}
+
+	s.instances[pe] = inst
+	s.order = append(s.order, scopedInstance{key: key, pe: pe, inst: inst})
+	s.mu.Unlock()
+
+	return inst, nil
+}
+
+// Dispose releases every instance created in this scope, in reverse creation order,
+// using the provider's WithClose function or io.Closer. All close errors are joined together.
+// Dispose is idempotent, and the scope cannot be used afterwards.
+func (s *Scope) Dispose() error {
+
+	s.mu.Lock()
+	if s.disposed {
+
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.disposed = true
+	order := s.order
+	s.order = nil
+	s.instances = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+
+		if err := closeInstance(order[i].pe, order[i].inst); err != nil {
+
+			errs = append(errs, fmt.Errorf("dispose %q :: %w", order[i].key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
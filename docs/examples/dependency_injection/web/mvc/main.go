@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/annuums/solanum"
 	"github.com/annuums/solanum/docs/examples/dependency_injection/web/mvc/user"
+	"log"
 )
 
 func main() {
@@ -16,5 +17,7 @@ func main() {
 		user.NewModule(""),
 	)
 
-	app.Run()
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -9,16 +9,8 @@ func NewController() *solanum.SolaController {
 	ctrl := solanum.NewController()
 
 	ctrl.SetHandlers(
-		&solanum.SolaService{
-			Uri:     "/users",
-			Method:  http.MethodGet,
-			Handler: retrieveUser,
-		},
-		&solanum.SolaService{
-			Uri:     "/users",
-			Method:  http.MethodPost,
-			Handler: addUser,
-		},
+		solanum.Handle(http.MethodGet, "/users", retrieveUser),
+		solanum.Handle(http.MethodPost, "/users", addUser),
 	)
 
 	return ctrl
@@ -1,37 +1,28 @@
 package user
 
 import (
+	"context"
 	"github.com/annuums/solanum/container"
-	"github.com/gin-gonic/gin"
-	"net/http"
 	"time"
 )
 
-func retrieveUser(c *gin.Context) {
-	repo := container.DepFromGinContext[UserRepository](c, "userRepository")
+type createUserRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+}
 
-	users, err := repo.FindAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, users)
+func retrieveUser(ctx context.Context, _ struct{}) ([]User, error) {
+	repo := container.DepFromContext[UserRepository](ctx, "userRepository")
+
+	return repo.FindAll()
 }
 
-func addUser(c *gin.Context) {
-	var req struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
-	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+func addUser(ctx context.Context, req createUserRequest) (*User, error) {
 	u := &User{Name: req.Name, Email: req.Email, CreatedAt: time.Now()}
-	repo := container.DepFromContext[UserRepository](c, "userRepository")
+	repo := container.DepFromContext[UserRepository](ctx, "userRepository")
 	if err := repo.Create(u); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		return nil, err
 	}
-	c.JSON(http.StatusCreated, u)
+
+	return u, nil
 }
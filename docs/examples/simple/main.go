@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/annuums/solanum"
 	"github.com/gin-gonic/gin"
+	"log"
 	"net/http"
 )
 
@@ -29,5 +30,7 @@ func main() {
 	)
 
 	server.SetModules(pingModule)
-	server.Run()
+	if err := server.Run(); err != nil {
+		log.Fatal(err)
+	}
 }
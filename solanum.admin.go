@@ -0,0 +1,259 @@
+package solanum
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/annuums/solanum/container"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// AdminReport is the diagnostic view of the application served by the admin module.
+	AdminReport struct {
+		// Routes every route of the registered modules
+		Routes []AdminRoute `json:"routes"`
+
+		// Middlewares the names of the global middlewares in the order they run
+		Middlewares []string `json:"middlewares"`
+
+		// Containers every container in use, the runner's first
+		Containers []AdminContainer `json:"containers"`
+
+		// Cors the effective CORS configuration; nil if CORS is not configured
+		Cors *AdminCors `json:"cors"`
+	}
+
+	// AdminRoute describes a route and the handlers it runs.
+	AdminRoute struct {
+		// Method the HTTP method of the route
+		Method string `json:"method"`
+
+		// Path the full Gin path of the route
+		Path string `json:"path"`
+
+		// Module the full path of the module mounting the route
+		Module string `json:"module"`
+
+		// Handlers the names of the handlers run, in order, as reported by Runner.Chain
+		Handlers []string `json:"handlers"`
+	}
+
+	// AdminContainer describes a container and its providers.
+	AdminContainer struct {
+		// Name "runner" for the runner's container, otherwise the path of the first module using it
+		Name string `json:"name"`
+
+		// Parent the name of the container this one falls back to, if it is in use
+		Parent string `json:"parent,omitempty"`
+
+		// Providers the providers registered in the container itself
+		Providers []container.ProviderInfo `json:"providers"`
+	}
+
+	// AdminCors describes the effective CORS configuration.
+	AdminCors struct {
+		AllowOrigins     []string `json:"allowOrigins"`
+		AllowMethods     []string `json:"allowMethods"`
+		AllowHeaders     []string `json:"allowHeaders"`
+		AllowCredentials bool     `json:"allowCredentials"`
+		OriginFunc       bool     `json:"originFunc"` // whether a custom origin function is set
+		MaxAge           string   `json:"maxAge"`
+	}
+)
+
+// WithAdmin serves diagnostics at uri (e.g., "/admin"), behind the auth middleware,
+// which must abort unauthorized requests (see BearerToken): the full report at uri,
+// and its parts at uri+"/routes", uri+"/containers" and uri+"/cors". See AdminReport.
+// Without an auth middleware, the admin module is not served.
+func WithAdmin(uri string, auth gin.HandlerFunc) option {
+
+	return func(r Runner) {
+
+		server, ok := r.(*runner)
+		if !ok {
+
+			slog.Default().Warn("unable to set admin: Runner is not of type *runner")
+			return
+		}
+
+		if auth == nil {
+
+			server.Logger().Warn("admin module not served: it needs an auth middleware", "uri", uri)
+			return
+		}
+
+		server.SetModules(server.adminModule(uri, auth))
+	}
+}
+
+// BearerToken returns an auth middleware accepting requests with the header
+// "Authorization: Bearer <token>" and aborting others with 401. An empty token
+// accepts no request.
+func BearerToken(token string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+
+			c.Header("WWW-Authenticate", "Bearer")
+			abortWithError(c, &Error{Status: http.StatusUnauthorized, Code: "unauthorized"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Admin returns the diagnostic view of the application. Handlers are only known
+// once modules are initialized.
+func (server *runner) Admin() AdminReport {
+
+	report := AdminReport{
+		Routes:     server.adminRoutes(),
+		Containers: server.adminContainers(),
+		Cors:       server.adminCors(),
+	}
+
+	report.Middlewares, _ = server.Middlewares()
+
+	return report
+}
+
+// adminRoutes describes the routes of the registered modules.
+func (server *runner) adminRoutes() []AdminRoute {
+
+	routes := make([]AdminRoute, 0)
+	for _, info := range server.Routes() {
+
+		handlers, _ := server.Chain(info.Method, info.Path)
+		routes = append(routes, AdminRoute{
+			Method:   info.Method,
+			Path:     info.Path,
+			Module:   info.ModulePath,
+			Handlers: handlers,
+		})
+	}
+
+	return routes
+}
+
+// adminContainers describes the containers in use, named after the modules using them.
+func (server *runner) adminContainers() []AdminContainer {
+
+	names := make(map[*container.Container]string)
+
+	var walk func(m Module, path string)
+	walk = func(m Module, path string) {
+
+		if holder, ok := m.(containerHolder); ok {
+
+			if _, named := names[holder.Container()]; !named {
+
+				names[holder.Container()] = path
+			}
+		}
+
+		if parent, ok := m.(ParentModule); ok {
+
+			for _, sub := range parent.SubModules() {
+
+				walk(sub, joinPaths(path, sub.Uri()))
+			}
+		}
+	}
+
+	names[server.Container()] = "runner"
+	for _, m := range server.modules {
+
+		walk(*m, joinPaths("/", (*m).Uri()))
+	}
+
+	containers := make([]AdminContainer, 0)
+	for _, cont := range server.containers() {
+
+		containers = append(containers, AdminContainer{
+			Name:      names[cont],
+			Parent:    names[cont.Parent()],
+			Providers: cont.Describe(),
+		})
+	}
+
+	return containers
+}
+
+// adminCors describes the CORS configuration set with Cors, if any.
+func (server *runner) adminCors() *AdminCors {
+
+	if server.cors == nil {
+
+		return nil
+	}
+
+	return &AdminCors{
+		AllowOrigins:     server.cors.Urls,
+		AllowMethods:     server.cors.Methods,
+		AllowHeaders:     server.cors.Headers,
+		AllowCredentials: server.cors.AllowCredentials,
+		OriginFunc:       server.cors.OriginFunc != nil,
+		MaxAge:           (time.Duration(server.cors.MaxAge) * time.Hour).String(),
+	}
+}
+
+// adminModule returns the module serving the admin report at uri, behind auth.
+func (server *runner) adminModule(uri string, auth gin.HandlerFunc) *SolaModule {
+
+	ctr := NewController()
+	ctr.SetHandlers(
+		&SolaService{
+			Uri:    "",
+			Method: http.MethodGet,
+			Handler: func(c *gin.Context) {
+
+				c.JSON(http.StatusOK, server.Admin())
+			},
+			Meta:     map[string]any{MetaName: "admin", MetaSummary: "Routes, containers and CORS configuration"},
+			Response: AdminReport{},
+		},
+		&SolaService{
+			Uri:    "/routes",
+			Method: http.MethodGet,
+			Handler: func(c *gin.Context) {
+
+				c.JSON(http.StatusOK, server.adminRoutes())
+			},
+			Meta:     map[string]any{MetaName: "adminRoutes", MetaSummary: "Routes and their handlers"},
+			Response: []AdminRoute{},
+		},
+		&SolaService{
+			Uri:    "/containers",
+			Method: http.MethodGet,
+			Handler: func(c *gin.Context) {
+
+				c.JSON(http.StatusOK, server.adminContainers())
+			},
+			Meta:     map[string]any{MetaName: "adminContainers", MetaSummary: "Containers and their providers"},
+			Response: []AdminContainer{},
+		},
+		&SolaService{
+			Uri:    "/cors",
+			Method: http.MethodGet,
+			Handler: func(c *gin.Context) {
+
+				c.JSON(http.StatusOK, server.adminCors())
+			},
+			Meta:     map[string]any{MetaName: "adminCors", MetaSummary: "Effective CORS configuration"},
+			Response: AdminCors{},
+		},
+	)
+
+	module := NewModule(WithUri(uri))
+	module.SetPreMiddlewares(auth)
+	module.SetControllers(ctr)
+
+	return module
+}
@@ -0,0 +1,312 @@
+package solanum
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// MIMEProblemJSON is the media type of RFC 7807 problem details.
+	MIMEProblemJSON = "application/problem+json"
+
+	// errorLogged is the meta of errors attached to a request that are already
+	// logged, which ErrorHandler does not log again.
+	errorLogged = "solanum.logged"
+)
+
+type (
+	// Error is an error carrying the HTTP response it should produce. Handlers return it,
+	// or attach it with c.Error, to answer with a problem+json body built from its fields.
+	Error struct {
+		// Status the HTTP status code; 500 if unset
+		Status int
+
+		// Code an application-specific, machine-readable error code (e.g., "user_not_found")
+		Code string
+
+		// Title a short summary of the problem type; the status text if unset
+		Title string
+
+		// Detail an explanation specific to this occurrence of the problem, shown to clients
+		Detail string
+
+		// Fields messages about individual request fields, by field name
+		Fields map[string]string
+
+		// Err the underlying error, never shown to clients outside debug mode
+		Err error
+	}
+
+	// Problem is the RFC 7807 problem details body rendered for errors.
+	Problem struct {
+		Type     string            `json:"type"`
+		Title    string            `json:"title"`
+		Status   int               `json:"status"`
+		Detail   string            `json:"detail,omitempty"`
+		Instance string            `json:"instance,omitempty"`
+		Code     string            `json:"code,omitempty"`
+		Fields   map[string]string `json:"fields,omitempty"`
+	}
+
+	// errorMapping maps the errors matching it to a status and code.
+	errorMapping struct {
+		match  func(err error) bool
+		status int
+		code   string
+		detail string // the detail of client errors, e.g. the message of the sentinel
+	}
+)
+
+var (
+	errorMappingsMu sync.RWMutex
+	errorMappings   []errorMapping // in registration order; the last match wins
+)
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+
+	msg := e.Title
+	if msg == "" {
+
+		msg = http.StatusText(e.status())
+	}
+
+	if e.Detail != "" {
+
+		msg += " :: " + e.Detail
+	}
+
+	if e.Err != nil {
+
+		msg += " :: " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+
+	return e.Err
+}
+
+// status returns the status code of the error, defaulting to 500.
+func (e *Error) status() int {
+
+	if e.Status == 0 {
+
+		return http.StatusInternalServerError
+	}
+
+	return e.Status
+}
+
+// RegisterError maps every error matching target, as reported by errors.Is,
+// to the given status and code, e.g. RegisterError(sql.ErrNoRows, 404, "not_found").
+// For client errors, the message of target is the detail; the message of the error
+// wrapping it is only shown in debug mode. Mappings registered later take precedence.
+func RegisterError(target error, status int, code string) {
+
+	registerErrorMapping(errorMapping{
+		match:  func(err error) bool { return errors.Is(err, target) },
+		status: status,
+		code:   code,
+		detail: target.Error(),
+	})
+}
+
+// RegisterErrorType maps every error of type T, as reported by errors.As,
+// to the given status and code, without a detail, as the message of the error may
+// carry internals. Mappings registered later take precedence.
+func RegisterErrorType[T error](status int, code string) {
+
+	registerErrorMapping(errorMapping{
+		match: func(err error) bool {
+
+			var target T
+			return errors.As(err, &target)
+		},
+		status: status,
+		code:   code,
+	})
+}
+
+// registerErrorMapping adds a mapping to the error registry.
+func registerErrorMapping(m errorMapping) {
+
+	errorMappingsMu.Lock()
+	defer errorMappingsMu.Unlock()
+
+	errorMappings = append(errorMappings, m)
+}
+
+// AsError converts any error into an *Error: an *Error in the chain is returned as is,
+// validation errors become 400 Bad Request with a message per field, and registered
+// errors get their mapped status and code, with the message of the registered sentinel
+// as detail for client errors. Other errors become 500 Internal Server Error.
+func AsError(err error) *Error {
+
+	var e *Error
+	if errors.As(err, &e) {
+
+		return e
+	}
+
+	if e, ok := asBodyTooLarge(err); ok {
+
+		return e
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+
+		fields := make(map[string]string, len(verrs))
+		for _, fe := range verrs {
+
+			fields[fe.Field()] = fmt.Sprintf("failed on the %q rule", fe.Tag())
+		}
+
+		return &Error{
+			Status: http.StatusBadRequest,
+			Code:   "validation_failed",
+			Detail: "the request is invalid",
+			Fields: fields,
+			Err:    err,
+		}
+	}
+
+	errorMappingsMu.RLock()
+	defer errorMappingsMu.RUnlock()
+
+	for i := len(errorMappings) - 1; i >= 0; i-- {
+
+		if m := errorMappings[i]; m.match(err) {
+
+			e := &Error{Status: m.status, Code: m.code, Err: err}
+			if m.status < http.StatusInternalServerError {
+
+				// The wrapping error may carry internals, e.g. a query; ProblemOf only
+				// shows it in debug mode
+				e.Detail = m.detail
+			}
+
+			return e
+		}
+	}
+
+	return &Error{Status: http.StatusInternalServerError, Err: err}
+}
+
+// ProblemOf builds the problem details of err. Outside debug mode, the messages of
+// underlying errors are left out, as they may leak internals; in debug mode they are
+// used as the detail when none is set.
+func ProblemOf(err error, debug bool) *Problem {
+
+	e := AsError(err)
+
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  e.Title,
+		Status: e.status(),
+		Detail: e.Detail,
+		Code:   e.Code,
+		Fields: e.Fields,
+	}
+
+	if p.Title == "" {
+
+		p.Title = http.StatusText(p.Status)
+	}
+
+	if debug && e.Err != nil && p.Detail != e.Err.Error() {
+
+		if p.Detail == "" {
+
+			p.Detail = e.Err.Error()
+		} else {
+
+			p.Detail += " :: " + e.Err.Error()
+		}
+	}
+
+	return p
+}
+
+// abortWithError aborts the request with err, setting the status of its problem
+// without writing a body, which is left to ErrorHandler.
+func abortWithError(c *gin.Context, err error) {
+
+	_ = c.Error(err)
+	c.Status(AsError(err).status())
+	c.Abort()
+}
+
+// ErrorHandler returns a middleware rendering the last error attached to the request
+// with c.Error as an application/problem+json body, unless a response was already
+// written. Server errors are logged with slog.Default(). Set debug to expose the
+// messages of underlying errors.
+func ErrorHandler(debug bool) gin.HandlerFunc {
+
+	return errorHandler(func() bool { return debug }, slog.Default)
+}
+
+// errorHandler is ErrorHandler, reading the debug mode and the logger for every request.
+func errorHandler(debug func() bool, logger func() *slog.Logger) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		c.Next()
+
+		if len(c.Errors) == 0 {
+
+			return
+		}
+
+		err := c.Errors.Last().Err
+		p := ProblemOf(err, debug())
+		p.Instance = c.Request.URL.Path
+
+		if p.Status >= http.StatusInternalServerError && c.Errors.Last().Meta != errorLogged {
+
+			logger().ErrorContext(
+				c.Request.Context(),
+				"request failed",
+				"error", err,
+				"status", p.Status,
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"request_id", requestIDOf(c),
+			)
+		}
+
+		if c.Writer.Written() {
+
+			return
+		}
+
+		c.Header("Content-Type", MIMEProblemJSON)
+		c.JSON(p.Status, p)
+	}
+}
+
+// WithDebug exposes the messages of underlying errors in problem responses.
+// Keep it disabled in production, as they may leak internals.
+func WithDebug(debug bool) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.debug = debug
+		} else {
+
+			slog.Default().Warn("unable to set debug mode: Runner is not of type *runner")
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package solanum
 
 import (
+	"context"
 	"fmt"
 	"github.com/annuums/solanum/container"
 	"github.com/annuums/solanum/util"
+	"log/slog"
 	"reflect"
 	"time"
 
@@ -14,14 +16,35 @@ import (
 // SolanumRunner holds the global Runner instance used to configure and start the server.
 var SolanumRunner Runner
 
-// ValidateDependencies checks all registered modules for their dependencies.
+// ValidateDependencies checks all registered modules, including sub-modules, for their dependencies.
+// Each container in use is first checked for dependency cycles; then every declared
+// dependency is resolved, inside a throwaway scope so that scoped providers are covered too.
 func (server *runner) ValidateDependencies() error {
 
-	for _, mPtr := range server.modules {
+	ctx, scope := container.NewScope(context.Background())
+	defer func() { _ = scope.Dispose() }()
 
-		for _, dep := range *(*mPtr).Dependencies() {
+	checked := make(map[*container.Container]struct{})
+	for _, m := range server.allModules() {
 
-			inst, err := container.Resolve(dep.Key)
+		cont := server.Container()
+		if holder, ok := m.(containerHolder); ok {
+
+			cont = holder.Container()
+		}
+
+		if _, ok := checked[cont]; !ok {
+
+			checked[cont] = struct{}{}
+			if err := cont.CheckCycles(); err != nil {
+
+				return fmt.Errorf("dependency validation failed :: %w", err)
+			}
+		}
+
+		for _, dep := range *m.Dependencies() {
+
+			inst, err := cont.ResolveContext(ctx, dep.Key)
 			if err != nil {
 
 				return fmt.Errorf(
@@ -68,38 +91,26 @@ func (server *runner) ValidateDependencies() error {
 	return nil
 }
 
-// Run initializes all modules and starts the Gin HTTP server on the configured port.
-func (server *runner) Run() {
-
-	if err := server.ValidateDependencies(); err != nil {
+// InitModules applies the global middlewares registered with Use, then sets up
+// routing groups for each Module and applies their routes.
+// Start calls it once before listening; calling it again registers routes twice.
+func (server *runner) InitModules() {
 
-		panic("Dependency check failed :: " + err.Error())
-	}
+	server.Logger().Info("initializing modules", "modules", len(server.modules))
+	if !server.modulesInitialized {
 
-	if server.port == nil {
-
-		panic("Server port is not configured. Please set a port before running.")
+		server.useBodyLimit()
+		server.applyMiddlewares()
 	}
+	server.modulesInitialized = true
 
-	// Start Gin Server
-	if server.Port() != 0 {
-
-		SolanumRunner.InitModules()
+	if server.metrics != nil {
 
-		addr := fmt.Sprintf(":%d", *server.port)
-		fmt.Printf("Solanum is running on %s\n", addr)
+		for _, cont := range server.containers() {
 
-		if err := server.Engine.Run(addr); err != nil {
-
-			panic("fail to run server on addr :: " + addr + " :: " + err.Error())
+			cont.SetObserver(server.metrics)
 		}
 	}
-}
-
-// InitModules sets up routing groups for each Module and applies their routes.
-func (server *runner) InitModules() {
-
-	fmt.Println("Initialize Modules...")
 
 	for _, m := range server.modules {
 
@@ -109,9 +120,15 @@ func (server *runner) InitModules() {
 			),
 		)
 	}
+
+	if server.openAPIPath != "" {
+
+		server.serveOpenAPI()
+	}
 }
 
 // SetModules registers one or more Module implementations with the Runner.
+// SolaModules without a container of their own inherit the runner's container.
 func (server *runner) SetModules(m ...Module) {
 
 	if server.modules == nil {
@@ -121,6 +138,11 @@ func (server *runner) SetModules(m ...Module) {
 
 	for i := range m {
 
+		if sm, ok := m[i].(*SolaModule); ok && sm.container == nil {
+
+			sm.container = server.Container()
+		}
+
 		server.modules = append(server.modules, &m[i])
 	}
 }
@@ -131,9 +153,30 @@ func (server *runner) Modules() []*Module {
 	return server.modules
 }
 
-// InitGlobalMiddlewares is a placeholder for registering application-wide middlewares
-// such as logging, authentication, and authorization. Implement as needed.
+// InitGlobalMiddlewares registers application-wide middlewares: RequestTracing
+// identifying requests, the ErrorHandler rendering errors attached to requests as
+// problem+json, honoring WithDebug, and Recovery turning panics into errors, honoring
+// WithRecovery.
+// Logging, authentication, and authorization are left to implement as needed.
 func (server *runner) InitGlobalMiddlewares() {
+
+	server.unuse(MiddlewareRequestID)
+	server.unuse(MiddlewareErrors)
+	server.unuse(MiddlewareRecovery)
+
+	_ = server.Use(MiddlewareRequestID, RequestTracing(), WithPriority(-50))
+
+	_ = server.Use(
+		MiddlewareErrors,
+		errorHandler(func() bool { return server.debug }, server.Logger),
+		WithPriority(-30),
+	)
+	_ = server.Use(
+		MiddlewareRecovery,
+		recovery(func() gin.RecoveryFunc { return server.recovery }, server.Logger),
+		WithPriority(-20),
+	)
+
 	//* 1. Logger, ...
 
 	//* 2. Authentication, ...
@@ -141,13 +184,17 @@ func (server *runner) InitGlobalMiddlewares() {
 	//* 3. Authorization, ...
 }
 
-// Cors applies configured CORS settings to the Gin engine using the cors middleware.
-// Accepts functional options for customizing allowed origins, methods, headers, etc.
+// Cors registers the cors middleware as the MiddlewareCors global middleware,
+// replacing any previous configuration. Accepts functional options for customizing
+// allowed origins, methods, headers, etc.
 func (server *runner) Cors(opts ...func(*util.CorsOption)) {
 
 	options := util.CorsOptions(opts...)
+	server.cors = options
 
-	server.Engine.Use(
+	server.unuse(MiddlewareCors)
+	err := server.Use(
+		MiddlewareCors,
 		cors.New(
 			cors.Config{
 				AllowOrigins:     options.Urls,
@@ -158,7 +205,12 @@ func (server *runner) Cors(opts ...func(*util.CorsOption)) {
 				MaxAge:           time.Duration(options.MaxAge) * time.Hour,
 			},
 		),
+		WithPriority(-10),
 	)
+	if err != nil {
+
+		server.Logger().Warn("unable to set CORS", "error", err)
+	}
 }
 
 // GinEngine returns the underlying *gin.Engine for direct access and customization.
@@ -167,6 +219,17 @@ func (server *runner) GinEngine() *gin.Engine {
 	return server.Engine
 }
 
+// Container returns the DI container of the application.
+func (server *runner) Container() *container.Container {
+
+	if server.container == nil {
+
+		return container.Default()
+	}
+
+	return server.container
+}
+
 // Port returns the configured port for the HTTP server.
 func (server *runner) Port() int {
 
@@ -189,13 +252,43 @@ func WithPort(port int) option {
 			runner.port = &port
 		} else {
 
-			fmt.Println("⚠️ Unable to set port: Runner is not of type *runner")
+			slog.Default().Warn("unable to set port: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithContainer makes the runner, and every module without a container of its own,
+// resolve dependencies from c instead of container.Default(). Modules already registered
+// with the runner's previous container follow it to c.
+func WithContainer(c *container.Container) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			previous := runner.Container()
+			for _, m := range runner.modules {
+
+				if sm, ok := (*m).(*SolaModule); ok && sm.container == previous {
+
+					sm.container = c
+				}
+			}
+
+			runner.container = c
+		} else {
+
+			slog.Default().Warn("unable to set container: Runner is not of type *runner")
 		}
 	}
 }
 
-// NewSolanum creates (once) and returns the global Runner configured for the given port.
-// It ensures global middlewares are initialized. Subsequent calls return the same Runner.
+// NewSolanum creates (once) and returns the global Runner configured with the given options.
+// It ensures global middlewares are initialized and the runner's logger is registered
+// in its container. Without a port, or with port 0, the server binds a random port;
+// Addr reports it once started. Subsequent calls apply their options to the same Runner.
+// The global Runner resolves from container.Default() unless set with WithContainer;
+// use New for independent runners.
 func NewSolanum(opts ...option) Runner {
 
 	if SolanumRunner == nil {
@@ -210,26 +303,51 @@ func NewSolanum(opts ...option) Runner {
 		}
 	}
 
-	port := SolanumRunner.Port()
+	if r, ok := SolanumRunner.(*runner); ok {
 
-	if port == 0 {
-
-		fmt.Println("⚠️ No port specified, using default port 0 (random port).")
-		port = 0
+		r.init()
 	}
 
-	if port == 0 {
+	return SolanumRunner
+}
 
-		SolanumRunner = &runner{}
-	} else {
+// New creates an independent Runner configured with the given options, with an engine,
+// modules and global middlewares of its own, and its own container, created with
+// container.New() unless set with WithContainer. Unlike NewSolanum, it leaves the global
+// Runner alone, so several runners can serve different ports in one binary, or run in
+// parallel tests. Modules using WithDependency validate it against their own container,
+// so they should set it first with WithModuleContainer.
+func New(opts ...option) Runner {
 
-		SolanumRunner = &runner{
-			Engine: gin.New(),
-			port:   &port,
+	server := &runner{container: container.New()}
+	for _, opt := range opts {
+
+		if opt != nil {
+			opt(server)
 		}
+	}
+
+	server.init()
 
-		SolanumRunner.InitGlobalMiddlewares()
+	return server
+}
+
+// init completes the configuration of a runner once its options are applied: it
+// defaults the port to a random one, creates the engine with the global middlewares,
+// and registers the logger in the runner's container.
+func (server *runner) init() {
+
+	if server.port == nil {
+
+		server.Logger().Warn("no port specified, using port 0 (random port)")
+		server.port = new(int)
 	}
 
-	return SolanumRunner
+	if server.Engine == nil {
+
+		server.Engine = gin.New()
+		server.InitGlobalMiddlewares()
+	}
+
+	server.registerLogger()
 }
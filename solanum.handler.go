@@ -0,0 +1,227 @@
+package solanum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// StatusCoder can be implemented by the response of a typed handler to choose
+// its HTTP status code, instead of the default of the method (see Handle).
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Handle adapts a typed function to a SolaService. For every request it:
+//
+//  1. decodes the body, if any, into a new Req: as JSON if the Content-Type is JSON or
+//     missing, else with binding.Default, e.g. form-encoded fields into form tags
+//  2. binds the fields tagged uri, form and header from path params, the query string and headers
+//  3. validates Req with binding.Validator (go-playground/validator, "binding" tags)
+//  4. calls fn with the request's context, which carries the injected dependencies
+//  5. writes the returned Resp as JSON, with 201 Created for POST and 200 OK otherwise,
+//     unless Resp implements StatusCoder; a 204 No Content status writes no body
+//
+// Binding and validation failures abort the request with 400 Bad Request, and an error
+// returned by fn with the status of AsError. Either is attached with c.Error, for the
+// runner's ErrorHandler to render as problem+json. The service's Request and Response
+// are set from Req and Resp, so they are described by OpenAPI.
+func Handle[Req, Resp any](method, uri string, fn func(ctx context.Context, req Req) (Resp, error)) *SolaService {
+
+	var req Req
+	var resp Resp
+
+	return &SolaService{
+		Uri:      uri,
+		Method:   method,
+		Handler:  handler(method, fn),
+		Meta:     map[string]any{MetaStatus: successStatus(method, resp)},
+		Request:  req,
+		Response: resp,
+	}
+}
+
+// handler returns the gin.HandlerFunc behind a typed handler.
+func handler[Req, Resp any](method string, fn func(ctx context.Context, req Req) (Resp, error)) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		req, err := bind[Req](c)
+		if err != nil {
+
+			abortWithError(c, err)
+			return
+		}
+
+		resp, err := fn(c.Request.Context(), req)
+		if err != nil {
+
+			abortWithError(c, err)
+			return
+		}
+
+		status := successStatus(method, resp)
+		if status == http.StatusNoContent {
+
+			c.Status(status)
+			return
+		}
+
+		c.JSON(status, resp)
+	}
+}
+
+// bind builds a Req from the request and validates it. If Req is a pointer type,
+// the value it points to is allocated and bound.
+func bind[Req any](c *gin.Context) (Req, error) {
+
+	var req Req
+	target := any(&req)
+	if t := reflect.TypeOf(req); t != nil && t.Kind() == reflect.Pointer {
+
+		req = reflect.New(t.Elem()).Interface().(Req)
+		target = req
+	}
+
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+
+		if err := bindBody(c, target); err != nil {
+
+			return req, err
+		}
+	}
+
+	t := indirect(reflect.TypeOf(target))
+	if t.Kind() == reflect.Struct {
+
+		params := make(map[string][]string, len(c.Params))
+		for _, p := range c.Params {
+
+			params[p.Key] = []string{p.Value}
+		}
+
+		headers := make(map[string][]string)
+		for _, f := range fields(t) {
+
+			if tag, ok := f.Tag.Lookup("header"); ok && tag != "-" {
+
+				name, _, _ := strings.Cut(tag, ",")
+				headers[name] = c.Request.Header.Values(name)
+			}
+		}
+
+		sources := []struct {
+			tag  string
+			form map[string][]string
+		}{
+			{"uri", params},
+			{"form", c.Request.URL.Query()},
+			{"header", headers},
+		}
+
+		for _, src := range sources {
+
+			// Fields without the tag are matched by name, so only bind sources the type opts into
+			if !hasTag(t, src.tag) {
+
+				continue
+			}
+
+			if err := binding.MapFormWithTag(target, src.form, src.tag); err != nil {
+
+				return req, badRequest(fmt.Errorf("invalid request %s parameter :: %w", src.tag, err))
+			}
+		}
+	}
+
+	if binding.Validator != nil {
+
+		if err := binding.Validator.ValidateStruct(target); err != nil {
+
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+// bindBody decodes the request body into target: as JSON if the Content-Type is JSON or
+// missing, else with the binding of the Content-Type, e.g. form fields into form tags.
+// Validation is left to bind, once every source is bound.
+func bindBody(c *gin.Context, target any) error {
+
+	contentType := c.ContentType()
+	if contentType == "" || contentType == binding.MIMEJSON || strings.HasSuffix(contentType, "+json") {
+
+		if err := json.NewDecoder(c.Request.Body).Decode(target); err != nil && !errors.Is(err, io.EOF) {
+
+			return badRequest(fmt.Errorf("invalid request body :: %w", err))
+		}
+
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if err := binding.Default(c.Request.Method, contentType).Bind(c.Request, target); err != nil && !errors.As(err, &verrs) {
+
+		return badRequest(fmt.Errorf("invalid request body :: %w", err))
+	}
+
+	return nil
+}
+
+// badRequest wraps a binding error into a 400 Bad Request *Error describing it.
+func badRequest(err error) *Error {
+
+	if e, ok := asBodyTooLarge(err); ok {
+
+		return e
+	}
+
+	return &Error{Status: http.StatusBadRequest, Code: "invalid_request", Detail: err.Error(), Err: err}
+}
+
+// successStatus returns the status code of a successful response to method.
+func successStatus(method string, resp any) int {
+
+	// A nil pointer response cannot be asked for its status
+	if v := reflect.ValueOf(resp); v.Kind() == reflect.Pointer && v.IsNil() {
+
+		resp = nil
+	}
+
+	if sc, ok := resp.(StatusCoder); ok {
+
+		return sc.StatusCode()
+	}
+
+	if method == http.MethodPost {
+
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+// hasTag reports whether a field of struct type t has the given tag.
+func hasTag(t reflect.Type, tag string) bool {
+
+	for _, f := range fields(t) {
+
+		if _, ok := f.Tag.Lookup(tag); ok {
+
+			return true
+		}
+	}
+
+	return false
+}
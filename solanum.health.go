@@ -0,0 +1,245 @@
+package solanum
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/annuums/solanum/container"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultHealthCheckTimeout bounds how long each readiness check may take.
+	DefaultHealthCheckTimeout = 5 * time.Second
+
+	// HealthStatusOK reports a passing check, or a ready application.
+	HealthStatusOK = "ok"
+
+	// HealthStatusFail reports a failing check, or an application with a failing check.
+	HealthStatusFail = "fail"
+
+	// HealthStatusDraining reports an application shutting down, no longer ready.
+	HealthStatusDraining = "draining"
+)
+
+type (
+	// HealthReport is the result of the readiness checks, served as JSON by /readyz.
+	HealthReport struct {
+		// Status HealthStatusOK if every check passed, HealthStatusFail if one failed,
+		// or HealthStatusDraining during graceful shutdown
+		Status string `json:"status"`
+
+		// Checks the result of every check, by name; none while draining
+		Checks map[string]CheckResult `json:"checks,omitempty"`
+	}
+
+	// CheckResult is the result of one readiness check.
+	CheckResult struct {
+		// Status HealthStatusOK or HealthStatusFail
+		Status string `json:"status"`
+
+		// Error the reason the check failed, if it did
+		Error string `json:"error,omitempty"`
+
+		// Duration how long the check took (e.g., "1.2ms")
+		Duration string `json:"duration"`
+	}
+
+	// healthConfig configures the readiness checks.
+	healthConfig struct {
+		timeout time.Duration           // per-check timeout
+		checks  []container.HealthCheck // checks added with WithReadinessCheck
+	}
+
+	healthOption func(*healthConfig)
+)
+
+// WithHealth serves a liveness endpoint at /healthz, answering 200 while the server
+// runs, and a readiness endpoint at /readyz, answering 200 if every readiness check
+// passes and 503 otherwise, with a HealthReport. See Readiness for the checks run.
+func WithHealth(opts ...healthOption) option {
+
+	return func(r Runner) {
+
+		server, ok := r.(*runner)
+		if !ok {
+
+			slog.Default().Warn("unable to set health: Runner is not of type *runner")
+			return
+		}
+
+		if server.health != nil {
+
+			return
+		}
+
+		server.health = &healthConfig{timeout: DefaultHealthCheckTimeout}
+		for _, opt := range opts {
+
+			opt(server.health)
+		}
+
+		server.SetModules(server.healthModule())
+	}
+}
+
+// WithCheckTimeout sets how long each readiness check may take before it fails.
+// Defaults to DefaultHealthCheckTimeout.
+func WithCheckTimeout(timeout time.Duration) healthOption {
+
+	return func(cfg *healthConfig) {
+
+		cfg.timeout = timeout
+	}
+}
+
+// WithReadinessCheck adds a readiness check under name, besides those of the
+// container providers, e.g. to check a service no provider wraps.
+func WithReadinessCheck(name string, check func(ctx context.Context) error) healthOption {
+
+	return func(cfg *healthConfig) {
+
+		cfg.checks = append(cfg.checks, container.HealthCheck{Key: name, Check: check})
+	}
+}
+
+// Readiness runs the readiness checks concurrently, each bounded by the check timeout:
+// those added with WithReadinessCheck, and the health checks of the singletons of every
+// container in use (see container.WithHealthCheck and container.HealthChecker), named
+// by key. Once Shutdown has started, it reports HealthStatusDraining without running them.
+func (server *runner) Readiness(ctx context.Context) HealthReport {
+
+	if server.draining.Load() {
+
+		return HealthReport{Status: HealthStatusDraining}
+	}
+
+	timeout := DefaultHealthCheckTimeout
+	var checks []container.HealthCheck
+	if server.health != nil {
+
+		timeout = server.health.timeout
+		checks = append(checks, server.health.checks...)
+	}
+
+	seen := make(map[string]struct{}, len(checks))
+	for _, check := range checks {
+
+		seen[check.Key] = struct{}{}
+	}
+
+	for _, cont := range server.containers() {
+
+		for _, check := range cont.HealthChecks() {
+
+			// A key shadowed in a module container is checked once
+			if _, dup := seen[check.Key]; !dup {
+
+				seen[check.Key] = struct{}{}
+				checks = append(checks, check)
+			}
+		}
+	}
+
+	report := HealthReport{Status: HealthStatusOK, Checks: make(map[string]CheckResult, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+
+		wg.Add(1)
+		go func(check container.HealthCheck) {
+
+			defer wg.Done()
+
+			result := runCheck(ctx, timeout, check.Check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[check.Key] = result
+			if result.Status != HealthStatusOK {
+
+				report.Status = HealthStatusFail
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runCheck runs a check, failing it once timeout elapses even if it ignores its context.
+func runCheck(ctx context.Context, timeout time.Duration, check func(context.Context) error) CheckResult {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s :: %w", timeout, ctx.Err())
+	}
+
+	result := CheckResult{Status: HealthStatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+
+		result.Status = HealthStatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// healthModule returns the module serving /healthz and /readyz.
+func (server *runner) healthModule() *SolaModule {
+
+	ctr := NewController()
+	ctr.SetHandlers(
+		&SolaService{
+			Uri:    "/healthz",
+			Method: http.MethodGet,
+			Handler: func(c *gin.Context) {
+
+				c.JSON(http.StatusOK, HealthReport{Status: HealthStatusOK})
+			},
+			Meta:     map[string]any{MetaName: "liveness", MetaSummary: "Liveness of the server"},
+			Response: HealthReport{},
+		},
+		&SolaService{
+			Uri:    "/readyz",
+			Method: http.MethodGet,
+			Handler: func(c *gin.Context) {
+
+				report := server.Readiness(c.Request.Context())
+
+				status := http.StatusOK
+				if report.Status != HealthStatusOK {
+
+					status = http.StatusServiceUnavailable
+				}
+
+				c.JSON(status, report)
+			},
+			Meta:     map[string]any{MetaName: "readiness", MetaSummary: "Readiness of the application, with the result of every check"},
+			Response: HealthReport{},
+		},
+	)
+
+	module := NewModule(WithUri(""))
+	module.SetControllers(ctr)
+
+	return module
+}
@@ -0,0 +1,83 @@
+package solanum
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// WithH2C serves cleartext HTTP/2 (h2c) alongside HTTP/1.1 on the same listener, e.g.
+// behind a service mesh talking HTTP/2 to upstreams. Clients may use HTTP/2 with prior
+// knowledge or upgrade with "Upgrade: h2c". Shutdown drains h2c connections like any
+// other: they get a GOAWAY, and requests in flight on them may complete. HTTP/2 over TLS
+// is enabled whenever TLS is configured, with or without this option.
+// Server push is not supported, over h2c or TLS, as Go's HTTP/2 server does not push.
+func WithH2C() option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.h2c = true
+		} else {
+
+			slog.Default().Warn("unable to set h2c: Runner is not of type *runner")
+		}
+	}
+}
+
+// serveH2C makes srv serve h2c alongside HTTP/1.1. Its HTTP/2 server is registered with
+// srv, so that srv.Shutdown sends a GOAWAY on h2c connections too. As they are hijacked
+// from srv, which does not wait for them, the requests in flight on them are counted in
+// requests, for Shutdown to wait for.
+func serveH2C(srv *http.Server, requests *sync.WaitGroup) error {
+
+	h2s := &http2.Server{}
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+
+		return fmt.Errorf("fail to configure h2c :: %w", err)
+	}
+
+	handler := srv.Handler
+	srv.Handler = h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.ProtoMajor == 2 && r.TLS == nil {
+
+			requests.Add(1)
+			defer requests.Done()
+		}
+
+		handler.ServeHTTP(w, r)
+	}), h2s)
+
+	return nil
+}
+
+// waitH2C waits for the requests in flight on h2c connections to complete, or for ctx
+// to expire.
+func waitH2C(ctx context.Context, requests *sync.WaitGroup) error {
+
+	if requests == nil {
+
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+
+		requests.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
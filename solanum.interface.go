@@ -1,9 +1,12 @@
 package solanum
 
 import (
+	"context"
 	"github.com/annuums/solanum/container"
 	"github.com/annuums/solanum/util"
 	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net"
 )
 
 type (
@@ -17,6 +20,19 @@ type (
 		SetHandlers(handler ...*SolaService)
 	}
 
+	// RoutedController is an optional interface for controllers that contribute a sub-path
+	// and their own middleware, applied to every handler they return.
+	RoutedController interface {
+		Controller
+
+		// Prefix returns a sub-path mounted between the module URI and each handler's Uri.
+		Prefix() string
+
+		// Middlewares returns middleware to run after the module's pre-middleware
+		// and before each handler of this controller.
+		Middlewares() []gin.HandlerFunc
+	}
+
 	// Module represents a self-contained HTTP module with its own URI prefix,
 	// middleware layers, controllers, and dependencies.
 	Module interface {
@@ -57,6 +73,31 @@ type (
 		Uri() string
 	}
 
+	// ParentModule is implemented by modules that mount sub-modules below their URI
+	// (see SolaModule.AddSubModules).
+	ParentModule interface {
+		SubModules() []Module
+	}
+
+	// ModuleStarter is implemented by modules that need to run code when the application
+	// starts, e.g. to warm caches or start background consumers. OnStart runs after the
+	// providers' start hooks and before the server listens; an error aborts startup.
+	ModuleStarter interface {
+		OnStart(ctx context.Context) error
+	}
+
+	// ModuleStopper is implemented by modules that need to run code when the application
+	// shuts down, e.g. to flush buffers. OnStop runs after the server stopped serving requests.
+	ModuleStopper interface {
+		OnStop(ctx context.Context) error
+	}
+
+	// containerHolder is implemented by modules that resolve dependencies from
+	// their own container rather than the runner's.
+	containerHolder interface {
+		Container() *container.Container
+	}
+
 	// Runner is the application entrypoint interface for Solanum.
 	// It manages module initialization, global middlewares, CORS, and server start.
 	Runner interface {
@@ -72,12 +113,37 @@ type (
 		// SetModules registers one or more Modules with the Runner.
 		SetModules(m ...Module)
 
+		// Logger returns the logger the runner uses for startup, module initialization and errors.
+		Logger() *slog.Logger
+
+		// Routes lists every route of the registered modules, with full paths and service metadata.
+		Routes() []RouteInfo
+
+		// OpenAPI builds an OpenAPI 3.1 document describing the routes of the registered modules.
+		OpenAPI() *OpenAPIDocument
+
 		// GinEngine exposes the underlying *gin.Engine for custom setup.
 		GinEngine() *gin.Engine
 
-		// Port exposes the configured port for the HTTP server.
+		// Container exposes the DI container the application resolves dependencies from.
+		Container() *container.Container
+
+		// Port exposes the configured port for the HTTP server; 0 binds a random port.
 		Port() int
 
+		// Addr returns the address the running server is bound to, or nil if it is not running.
+		Addr() net.Addr
+
+		// Use registers a global middleware under a unique name, ordered with WithPriority,
+		// RunBefore and RunAfter, and skipped for WithExcludePaths.
+		Use(name string, mw gin.HandlerFunc, opts ...middlewareOption) error
+
+		// Middlewares returns the names of the global middlewares in the order they run.
+		Middlewares() ([]string, error)
+
+		// Chain returns the names of the handlers run, in order, for a route.
+		Chain(method, path string) ([]string, error)
+
 		// Cors applies CORS configuration to the Gin engine using functional options.
 		Cors(opts ...func(*util.CorsOption))
 
@@ -85,6 +151,21 @@ type (
 		ValidateDependencies() error
 
 		// Run boots the HTTP server, initializing modules and listening on the configured port.
-		Run()
+		// It blocks until SIGINT/SIGTERM or a serve error, then shuts down gracefully.
+		Run() error
+
+		// Admin returns the diagnostic view of the application: routes, containers and CORS configuration.
+		Admin() AdminReport
+
+		// Readiness runs the readiness checks of the application and its container providers.
+		Readiness(ctx context.Context) HealthReport
+
+		// Start boots the HTTP server in the background and returns once it is listening.
+		Start(ctx context.Context) error
+
+		// Shutdown drains in-flight requests, stops the HTTP server and releases application
+		// resources, closing container singletons in reverse dependency order.
+		// All errors are joined together.
+		Shutdown(ctx context.Context) error
 	}
 )
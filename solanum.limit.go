@@ -0,0 +1,253 @@
+package solanum
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultReadHeaderTimeout bounds how long the server waits for request headers,
+	// so that slow clients cannot hold connections open (slowloris).
+	DefaultReadHeaderTimeout = 10 * time.Second
+
+	// MiddlewareBodyLimit limits the size of request bodies, set with WithMaxBodyBytes
+	// or WithModuleMaxBodyBytes (priority -15)
+	MiddlewareBodyLimit = "solanum.bodylimit"
+)
+
+// WithReadTimeout sets the maximum duration for reading an entire request, body
+// included. Defaults to no timeout.
+func WithReadTimeout(timeout time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.readTimeout = timeout
+		} else {
+
+			slog.Default().Warn("unable to set read timeout: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithReadHeaderTimeout sets the maximum duration for reading request headers.
+// Defaults to DefaultReadHeaderTimeout; a negative timeout disables it.
+func WithReadHeaderTimeout(timeout time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.readHeaderTimeout = timeout
+		} else {
+
+			slog.Default().Warn("unable to set read header timeout: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithWriteTimeout sets the maximum duration before timing out writes of a response,
+// from the end of the request headers. Defaults to no timeout.
+func WithWriteTimeout(timeout time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.writeTimeout = timeout
+		} else {
+
+			slog.Default().Warn("unable to set write timeout: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithIdleTimeout sets how long keep-alive connections wait for the next request.
+// Defaults to the read timeout, or no timeout if neither is set.
+func WithIdleTimeout(timeout time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.idleTimeout = timeout
+		} else {
+
+			slog.Default().Warn("unable to set idle timeout: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of request headers, request line included.
+// Defaults to http.DefaultMaxHeaderBytes (1 MB).
+func WithMaxHeaderBytes(n int) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.maxHeaderBytes = n
+		} else {
+
+			slog.Default().Warn("unable to set max header bytes: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithMaxBodyBytes limits request bodies to n bytes through the MiddlewareBodyLimit
+// global middleware. Requests declaring a larger Content-Length are rejected with 413
+// before their handler runs; reading past the limit fails with an error that renders
+// as 413. Modules can override the limit with WithModuleMaxBodyBytes.
+// Defaults to no limit.
+func WithMaxBodyBytes(n int64) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.maxBodyBytes = n
+		} else {
+
+			slog.Default().Warn("unable to set max body bytes: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithModuleMaxBodyBytes limits request bodies of the module's routes, and of its
+// sub-modules without a limit of their own, to n bytes, instead of the runner's limit
+// set with WithMaxBodyBytes; e.g. a higher limit for an upload module.
+// A negative n removes the limit for the module.
+func WithModuleMaxBodyBytes(n int64) moduleOption {
+
+	return func(m *SolaModule) error {
+
+		m.maxBodyBytes = n
+		return nil
+	}
+}
+
+// bodyLimit returns the body limit of the module's routes: its own, else its parent's;
+// zero means the runner's limit applies.
+func (m *SolaModule) bodyLimit() int64 {
+
+	for cur := m; cur != nil; cur = cur.parent {
+
+		if cur.maxBodyBytes != 0 {
+
+			return cur.maxBodyBytes
+		}
+	}
+
+	return 0
+}
+
+// useBodyLimit registers the MiddlewareBodyLimit global middleware if the runner or
+// any module limits request bodies.
+func (server *runner) useBodyLimit() {
+
+	limited := server.maxBodyBytes > 0
+	for _, m := range server.allModules() {
+
+		if sm, ok := m.(*SolaModule); ok && sm.maxBodyBytes != 0 {
+
+			limited = true
+		}
+	}
+
+	if !limited {
+
+		return
+	}
+
+	server.unuse(MiddlewareBodyLimit)
+	if err := server.Use(MiddlewareBodyLimit, bodyLimit(server.bodyLimitOf()), WithPriority(-15)); err != nil {
+
+		server.Logger().Warn("unable to limit request bodies", "error", err)
+	}
+}
+
+// bodyLimitOf returns a function giving the body limit of a route: the limit of the
+// module serving it, else the runner's. It is built from the routes of the modules on
+// first use.
+func (server *runner) bodyLimitOf() func(method, route string) int64 {
+
+	var once sync.Once
+	var limits map[string]int64
+
+	return func(method, route string) int64 {
+
+		once.Do(func() {
+
+			limits = make(map[string]int64)
+			for _, info := range server.Routes() {
+
+				if sm, ok := info.Module.(*SolaModule); ok && sm.bodyLimit() != 0 {
+
+					limits[info.Method+" "+info.Path] = sm.bodyLimit()
+				}
+			}
+		})
+
+		if limit, ok := limits[method+" "+route]; ok {
+
+			return limit
+		}
+
+		return server.maxBodyBytes
+	}
+}
+
+// bodyLimit returns a middleware limiting request bodies to the limit of their route.
+// Routes without a positive limit are left alone.
+func bodyLimit(limitOf func(method, route string) int64) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		limit := limitOf(c.Request.Method, c.FullPath())
+		if limit <= 0 {
+
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+
+			abortWithError(c, bodyTooLarge(&http.MaxBytesError{Limit: limit}))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// bodyTooLarge returns the 413 *Error of a request body over its limit.
+func bodyTooLarge(err *http.MaxBytesError) *Error {
+
+	return &Error{
+		Status: http.StatusRequestEntityTooLarge,
+		Code:   "body_too_large",
+		Detail: fmt.Sprintf("the request body exceeds %d bytes", err.Limit),
+		Err:    err,
+	}
+}
+
+// asBodyTooLarge returns the 413 *Error of err if it comes from reading a request body
+// over its limit.
+func asBodyTooLarge(err error) (*Error, bool) {
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+
+		return bodyTooLarge(tooLarge), true
+	}
+
+	return nil, false
+}
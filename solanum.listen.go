@@ -0,0 +1,183 @@
+package solanum
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+)
+
+// WithHost sets the host or IP address the server listens on (e.g., "127.0.0.1" to
+// accept local connections only). Defaults to every interface.
+func WithHost(host string) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.host = host
+		} else {
+
+			slog.Default().Warn("unable to set host: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithTLS serves HTTPS with the certificate and private key in the given PEM files,
+// loaded when the server starts. It can be combined with WithTLSConfig.
+func WithTLS(certFile, keyFile string) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.tlsCertFile, runner.tlsKeyFile = certFile, keyFile
+		} else {
+
+			slog.Default().Warn("unable to set TLS: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithTLSConfig serves HTTPS with the given configuration, which must provide the
+// server certificate unless WithTLS does. For mutual TLS between services, set ClientAuth
+// to tls.RequireAndVerifyClientCert and ClientCAs to the pool of trusted client CAs;
+// handlers find the verified client certificates in c.Request.TLS.PeerCertificates.
+func WithTLSConfig(cfg *tls.Config) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.tlsConfig = cfg
+		} else {
+
+			slog.Default().Warn("unable to set TLS config: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithUnixSocket serves on a Unix domain socket at path instead of a TCP port, e.g.
+// behind a sidecar proxy. A stale socket file left at path is removed first; the
+// socket file is removed again on Shutdown.
+func WithUnixSocket(path string) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.unixSocket = path
+		} else {
+
+			slog.Default().Warn("unable to set Unix socket: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithListener serves on ln instead of opening a listener, e.g. one inherited through
+// systemd socket activation, or bound by a test. The runner takes ownership of ln and
+// closes it on Shutdown, so ln serves a single Start; starting again after Shutdown
+// fails unless a new listener is set.
+func WithListener(ln net.Listener) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.listener = ln
+			runner.listenerUsed = false
+		} else {
+
+			slog.Default().Warn("unable to set listener: Runner is not of type *runner")
+		}
+	}
+}
+
+// listen returns the listener to serve on: the one set with WithListener, a Unix
+// socket set with WithUnixSocket, or else a TCP listener on the host and port.
+func (server *runner) listen(ctx context.Context) (net.Listener, error) {
+
+	if server.listener != nil && server.unixSocket != "" {
+
+		return nil, errors.New("conflicting listener options :: WithListener and WithUnixSocket are both set")
+	}
+
+	if server.listenerUsed {
+
+		return nil, errors.New("listener already used :: the listener set with WithListener was closed on Shutdown")
+	}
+
+	if server.listener != nil {
+
+		ln := server.listener
+		server.listener, server.listenerUsed = nil, true
+		return ln, nil
+	}
+
+	var lc net.ListenConfig
+	if server.unixSocket != "" {
+
+		if info, err := os.Stat(server.unixSocket); err == nil && info.Mode()&os.ModeSocket != 0 {
+
+			if err := os.Remove(server.unixSocket); err != nil {
+
+				return nil, fmt.Errorf("fail to remove stale socket :: %s :: %w", server.unixSocket, err)
+			}
+		}
+
+		ln, err := lc.Listen(ctx, "unix", server.unixSocket)
+		if err != nil {
+
+			return nil, fmt.Errorf("fail to listen on socket :: %s :: %w", server.unixSocket, err)
+		}
+
+		return ln, nil
+	}
+
+	addr := net.JoinHostPort(server.host, strconv.Itoa(server.Port()))
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+
+		return nil, fmt.Errorf("fail to listen on addr :: %s :: %w", addr, err)
+	}
+
+	return ln, nil
+}
+
+// serverTLSConfig returns the TLS configuration to serve with, with the certificate
+// set with WithTLS loaded, or nil if TLS is not configured.
+func (server *runner) serverTLSConfig() (*tls.Config, error) {
+
+	if server.tlsConfig == nil && server.tlsCertFile == "" && server.tlsKeyFile == "" {
+
+		return nil, nil
+	}
+
+	cfg := &tls.Config{}
+	if server.tlsConfig != nil {
+
+		cfg = server.tlsConfig.Clone()
+	}
+
+	if server.tlsCertFile != "" || server.tlsKeyFile != "" {
+
+		cert, err := tls.LoadX509KeyPair(server.tlsCertFile, server.tlsKeyFile)
+		if err != nil {
+
+			return nil, fmt.Errorf("fail to load TLS certificate :: %w", err)
+		}
+
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+
+	if len(cfg.Certificates) == 0 && cfg.GetCertificate == nil && cfg.GetConfigForClient == nil {
+
+		return nil, errors.New("TLS config has no server certificate")
+	}
+
+	return cfg, nil
+}
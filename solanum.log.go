@@ -0,0 +1,269 @@
+package solanum
+
+import (
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// LoggerKey is the container key the runner registers its *slog.Logger under.
+	LoggerKey = "logger"
+
+	// MiddlewareAccessLog logs every request, set with WithAccessLog (priority -40)
+	MiddlewareAccessLog = "solanum.accesslog"
+
+	// redacted replaces the values of redacted headers and query parameters.
+	redacted = "REDACTED"
+)
+
+// loggerContainers holds the containers runners registered their logger in, which
+// they may replace, unlike a logger registered by the application.
+var loggerContainers sync.Map
+
+type (
+	// accessLogConfig configures the access-log middleware.
+	accessLogConfig struct {
+		sampleRate      float64             // fraction of requests logged, server errors excepted
+		headers         []string            // request headers to log
+		redactedHeaders map[string]struct{} // canonical header names whose values are redacted
+		redactedQuery   map[string]struct{} // query parameters whose values are redacted
+	}
+
+	accessLogOption func(*accessLogConfig)
+)
+
+// Logger returns the logger of the runner, slog.Default() unless set with WithLogger.
+func (server *runner) Logger() *slog.Logger {
+
+	if server.logger == nil {
+
+		return slog.Default()
+	}
+
+	return server.logger
+}
+
+// registerLogger registers the runner's logger in its container under LoggerKey,
+// unless the container already has one it did not register, which is left alone.
+func (server *runner) registerLogger() {
+
+	cont := server.Container()
+	if _, ours := loggerContainers.Load(cont); cont.Has(LoggerKey) && !ours {
+
+		return
+	}
+
+	cont.Register(LoggerKey, server.Logger())
+	loggerContainers.Store(cont, struct{}{})
+}
+
+// WithLogger sets the logger the runner uses for startup, module initialization,
+// errors and access logs. It is registered in the runner's container under LoggerKey,
+// unless the container already has a logger registered by the application.
+func WithLogger(logger *slog.Logger) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.logger = logger
+		} else {
+
+			slog.Default().Warn("unable to set logger: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithAccessLog logs every request with the runner's logger, through the
+// MiddlewareAccessLog global middleware. See AccessLog for the options.
+func WithAccessLog(opts ...accessLogOption) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.unuse(MiddlewareAccessLog)
+			if err := runner.Use(
+				MiddlewareAccessLog,
+				accessLog(runner.Logger, opts...),
+				WithPriority(-40),
+			); err != nil {
+
+				runner.Logger().Warn("unable to set access log", "error", err)
+			}
+		} else {
+
+			slog.Default().Warn("unable to set access log: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithSampleRate logs only the given fraction of requests, between 0 and 1.
+// Requests failing with a server error are always logged. Defaults to 1.
+func WithSampleRate(rate float64) accessLogOption {
+
+	return func(cfg *accessLogConfig) {
+
+		cfg.sampleRate = rate
+	}
+}
+
+// WithLoggedHeaders adds the given request headers to access logs.
+func WithLoggedHeaders(names ...string) accessLogOption {
+
+	return func(cfg *accessLogConfig) {
+
+		cfg.headers = append(cfg.headers, names...)
+	}
+}
+
+// WithRedactedHeaders replaces the values of the given headers in access logs.
+// Authorization, Cookie and Proxy-Authorization are always redacted.
+func WithRedactedHeaders(names ...string) accessLogOption {
+
+	return func(cfg *accessLogConfig) {
+
+		for _, name := range names {
+
+			cfg.redactedHeaders[http.CanonicalHeaderKey(name)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactedQuery replaces the values of the given query parameters in access logs.
+func WithRedactedQuery(params ...string) accessLogOption {
+
+	return func(cfg *accessLogConfig) {
+
+		for _, param := range params {
+
+			cfg.redactedQuery[param] = struct{}{}
+		}
+	}
+}
+
+// AccessLog returns a middleware logging every request with logger once it completes:
+// its method, route template, path, query, status, latency, response bytes, client IP
+// and request ID. Server errors are logged at error level, client errors at warn level.
+func AccessLog(logger *slog.Logger, opts ...accessLogOption) gin.HandlerFunc {
+
+	return accessLog(func() *slog.Logger { return logger }, opts...)
+}
+
+// accessLog is AccessLog, reading the logger for every request.
+func accessLog(logger func() *slog.Logger, opts ...accessLogOption) gin.HandlerFunc {
+
+	cfg := &accessLogConfig{
+		sampleRate: 1,
+		redactedHeaders: map[string]struct{}{
+			"Authorization":       {},
+			"Cookie":              {},
+			"Proxy-Authorization": {},
+		},
+		redactedQuery: make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+
+		opt(cfg)
+	}
+
+	return func(c *gin.Context) {
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError && cfg.sampleRate < 1 && rand.Float64() >= cfg.sampleRate {
+
+			return
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("request_id", requestIDOf(c)),
+		}
+
+		if c.Request.URL.RawQuery != "" {
+
+			attrs = append(attrs, slog.String("query", cfg.query(c.Request.URL.Query())))
+		}
+
+		if len(cfg.headers) > 0 {
+
+			headers := make([]any, 0, len(cfg.headers))
+			for _, name := range cfg.headers {
+
+				if value := cfg.header(c.Request.Header, name); value != "" {
+
+					headers = append(headers, slog.String(name, value))
+				}
+			}
+
+			attrs = append(attrs, slog.Group("headers", headers...))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		logger().LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// header returns the value of a logged header, redacted if configured.
+func (cfg *accessLogConfig) header(h http.Header, name string) string {
+
+	value := strings.Join(h.Values(name), ", ")
+	if _, ok := cfg.redactedHeaders[http.CanonicalHeaderKey(name)]; ok && value != "" {
+
+		return redacted
+	}
+
+	return value
+}
+
+// query encodes a query string with the values of redacted parameters replaced.
+func (cfg *accessLogConfig) query(q url.Values) string {
+
+	for param := range cfg.redactedQuery {
+
+		if values, ok := q[param]; ok {
+
+			for i := range values {
+
+				values[i] = redacted
+			}
+		}
+	}
+
+	return q.Encode()
+}
+
+// requestIDOf returns the ID of the request, set by RequestTracing, or else sent by the client.
+func requestIDOf(c *gin.Context) string {
+
+	if id := RequestID(c.Request.Context()); id != "" {
+
+		return id
+	}
+
+	return c.GetHeader(HeaderRequestID)
+}
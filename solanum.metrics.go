@@ -0,0 +1,410 @@
+package solanum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MiddlewareMetrics records HTTP metrics, set with WithMetrics (priority -45)
+	MiddlewareMetrics = "solanum.metrics"
+
+	// metricsContentType is the media type of the Prometheus text exposition format.
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of latency histogram buckets.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type (
+	// Metrics collects HTTP and container metrics and exposes them in the Prometheus
+	// text exposition format. It implements container.Observer. Create it with
+	// NewMetrics; WithMetrics wires one into a runner.
+	Metrics struct {
+		requests         *metricFamily
+		requestDurations *metricFamily
+		inFlight         *metricFamily
+		resolves         *metricFamily
+		resolveDurations *metricFamily
+		singletons       *metricFamily
+	}
+
+	// metricFamily is a metric with all of its labeled series.
+	metricFamily struct {
+		name    string
+		help    string
+		kind    string    // counter, gauge or histogram
+		labels  []string  // label names
+		buckets []float64 // histogram bucket upper bounds
+
+		mu     sync.Mutex
+		series map[string]*metricSeries // by joined label values
+	}
+
+	// metricSeries is the value of a metric for one combination of label values.
+	metricSeries struct {
+		labelValues []string
+		value       float64  // counter and gauge value
+		counts      []uint64 // histogram counts per bucket, not cumulative
+		sum         float64  // histogram sum of observations
+		count       uint64   // histogram count of observations
+	}
+
+	// countingWriter writes formatted text, keeping count of the bytes written and the first error.
+	countingWriter struct {
+		w   *bufio.Writer
+		n   int64
+		err error
+	}
+)
+
+// NewMetrics creates an empty set of metrics.
+func NewMetrics() *Metrics {
+
+	return &Metrics{
+		requests: newMetricFamily(
+			"solanum_http_requests_total", "Total number of HTTP requests.",
+			"counter", nil, "module", "route", "method", "status",
+		),
+		requestDurations: newMetricFamily(
+			"solanum_http_request_duration_seconds", "Latency of HTTP requests.",
+			"histogram", DefaultBuckets, "module", "route", "method", "status",
+		),
+		inFlight: newMetricFamily(
+			"solanum_http_requests_in_flight", "Number of HTTP requests being served.",
+			"gauge", nil, "module", "route", "method",
+		),
+		resolves: newMetricFamily(
+			"solanum_container_resolves_total", "Total number of dependency resolutions.",
+			"counter", nil, "key", "result",
+		),
+		resolveDurations: newMetricFamily(
+			"solanum_container_resolve_duration_seconds", "Latency of dependency resolutions, dependencies included.",
+			"histogram", DefaultBuckets, "key",
+		),
+		singletons: newMetricFamily(
+			"solanum_container_singleton_construction_seconds", "Time taken to construct singletons.",
+			"gauge", nil, "key",
+		),
+	}
+}
+
+// newMetricFamily creates a metric without series.
+func newMetricFamily(name, help, kind string, buckets []float64, labels ...string) *metricFamily {
+
+	return &metricFamily{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*metricSeries),
+	}
+}
+
+// ObserveResolve implements container.Observer.
+func (m *Metrics) ObserveResolve(key string, d time.Duration, err error) {
+
+	result := "ok"
+	if err != nil {
+
+		result = "error"
+	}
+
+	m.resolves.add(1, key, result)
+	m.resolveDurations.observe(d.Seconds(), key)
+}
+
+// ObserveSingleton implements container.Observer.
+func (m *Metrics) ObserveSingleton(key string, d time.Duration) {
+
+	m.singletons.set(d.Seconds(), key)
+}
+
+// Middleware returns a middleware recording the count, latency and in-flight number
+// of requests, labeled by route template, method and status. The module label is
+// empty; use WithMetrics to label requests by the path of the module serving them.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+
+	return m.middleware(func(string, string) string { return "" })
+}
+
+// middleware is Middleware, labeling requests with the module path given by moduleOf.
+func (m *Metrics) middleware(moduleOf func(method, route string) string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		start := time.Now()
+		route := c.FullPath()
+		module := moduleOf(c.Request.Method, route)
+
+		m.inFlight.add(1, module, route, c.Request.Method)
+		defer m.inFlight.add(-1, module, route, c.Request.Method)
+
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.add(1, module, route, c.Request.Method, status)
+		m.requestDurations.observe(time.Since(start).Seconds(), module, route, c.Request.Method, status)
+	}
+}
+
+// Handler returns a handler serving the metrics in the Prometheus text exposition format.
+func (m *Metrics) Handler() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		c.Header("Content-Type", metricsContentType)
+		c.Status(http.StatusOK)
+		_, _ = m.WriteTo(c.Writer)
+	}
+}
+
+// Module returns a module serving the metrics at uri (e.g., "/metrics").
+func (m *Metrics) Module(uri string) *SolaModule {
+
+	ctr := NewController()
+	ctr.SetHandlers(&SolaService{
+		Uri:     "",
+		Method:  http.MethodGet,
+		Handler: m.Handler(),
+		Meta: map[string]any{
+			MetaName:    "metrics",
+			MetaSummary: "Metrics in the Prometheus text exposition format",
+		},
+	})
+
+	module := NewModule(WithUri(uri))
+	module.SetControllers(ctr)
+
+	return module
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, f := range []*metricFamily{
+		m.requests, m.requestDurations, m.inFlight,
+		m.resolves, m.resolveDurations, m.singletons,
+	} {
+
+		f.write(cw)
+	}
+
+	if cw.err == nil {
+
+		cw.err = cw.w.Flush()
+	}
+
+	return cw.n, cw.err
+}
+
+// WithMetrics collects HTTP metrics through the MiddlewareMetrics global middleware,
+// labeled by the path of the module serving each route, observes the containers in
+// use, and serves the metrics at uri (e.g., "/metrics") from a module of its own.
+func WithMetrics(uri string) option {
+
+	return func(r Runner) {
+
+		server, ok := r.(*runner)
+		if !ok {
+
+			slog.Default().Warn("unable to set metrics: Runner is not of type *runner")
+			return
+		}
+
+		if server.metrics != nil {
+
+			return
+		}
+
+		server.metrics = NewMetrics()
+		if err := server.Use(
+			MiddlewareMetrics,
+			server.metrics.middleware(server.modulePathOf()),
+			WithPriority(-45),
+		); err != nil {
+
+			server.Logger().Warn("unable to set metrics", "error", err)
+			return
+		}
+
+		server.SetModules(server.metrics.Module(uri))
+	}
+}
+
+// modulePathOf returns a function giving the path of the module serving a route,
+// built from the routes of the modules on first use.
+func (server *runner) modulePathOf() func(method, route string) string {
+
+	var once sync.Once
+	var paths map[string]string
+
+	return func(method, route string) string {
+
+		once.Do(func() {
+
+			paths = make(map[string]string)
+			for _, info := range server.Routes() {
+
+				paths[info.Method+" "+info.Path] = info.ModulePath
+			}
+		})
+
+		return paths[method+" "+route]
+	}
+}
+
+// add adds v to the series with the given label values.
+func (f *metricFamily) add(v float64, labelValues ...string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.get(labelValues).value += v
+}
+
+// set sets the series with the given label values to v.
+func (f *metricFamily) set(v float64, labelValues ...string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.get(labelValues).value = v
+}
+
+// observe records v in the histogram series with the given label values.
+func (f *metricFamily) observe(v float64, labelValues ...string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.get(labelValues)
+	s.sum += v
+	s.count++
+
+	if i := sort.SearchFloat64s(f.buckets, v); i < len(f.buckets) {
+
+		s.counts[i]++
+	}
+}
+
+// get returns the series with the given label values, creating it if needed.
+// The caller must hold f.mu.
+func (f *metricFamily) get(labelValues []string) *metricSeries {
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+
+		s = &metricSeries{labelValues: labelValues}
+		if f.kind == "histogram" {
+
+			s.counts = make([]uint64, len(f.buckets))
+		}
+
+		f.series[key] = s
+	}
+
+	return s
+}
+
+// write writes the metric in the text exposition format, series sorted by labels.
+func (f *metricFamily) write(w *countingWriter) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+
+		return
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+	for _, key := range keys {
+
+		s := f.series[key]
+		if f.kind != "histogram" {
+
+			w.printf("%s%s %s\n", f.name, f.labelPairs(s.labelValues, ""), formatFloat(s.value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, upper := range f.buckets {
+
+			cumulative += s.counts[i]
+			w.printf("%s_bucket%s %d\n", f.name, f.labelPairs(s.labelValues, formatFloat(upper)), cumulative)
+		}
+
+		w.printf("%s_bucket%s %d\n", f.name, f.labelPairs(s.labelValues, "+Inf"), s.count)
+		w.printf("%s_sum%s %s\n", f.name, f.labelPairs(s.labelValues, ""), formatFloat(s.sum))
+		w.printf("%s_count%s %d\n", f.name, f.labelPairs(s.labelValues, ""), s.count)
+	}
+}
+
+// labelPairs renders label values as {name="value",...}, with an le label for
+// histogram buckets unless le is empty.
+func (f *metricFamily) labelPairs(values []string, le string) string {
+
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+
+		pairs = append(pairs, f.labels[i]+`="`+escapeLabel(v)+`"`)
+	}
+
+	if le != "" {
+
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+
+	if len(pairs) == 0 {
+
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeLabel escapes a label value for the text exposition format.
+func escapeLabel(v string) string {
+
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// formatFloat renders a sample value for the text exposition format.
+func formatFloat(v float64) string {
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// printf writes formatted text unless a previous write failed.
+func (cw *countingWriter) printf(format string, args ...any) {
+
+	if cw.err != nil {
+
+		return
+	}
+
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
@@ -0,0 +1,397 @@
+package solanum
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Names of the global middlewares Solanum registers itself. Use them with
+// RunBefore and RunAfter to order custom middlewares around them.
+const (
+	// MiddlewareErrors renders errors as problem+json (priority -30)
+	MiddlewareErrors = "solanum.errors"
+
+	// MiddlewareRecovery recovers from panics (priority -20)
+	MiddlewareRecovery = "solanum.recovery"
+
+	// MiddlewareCors applies the CORS configuration set with Cors (priority -10)
+	MiddlewareCors = "solanum.cors"
+)
+
+type (
+	// middlewareEntry is a global middleware registered with Runner.Use.
+	middlewareEntry struct {
+		name     string
+		handler  gin.HandlerFunc
+		priority int      // lower runs first
+		before   []string // middlewares this one must run before
+		after    []string // middlewares this one must run after
+		exclude  []string // request paths the middleware is skipped for
+		seq      int      // registration order, breaking priority ties
+	}
+
+	// mountedRoute is a route mounted by a SolaModule, with its complete handler chain.
+	mountedRoute struct {
+		method   string
+		path     string
+		handlers gin.HandlersChain
+	}
+
+	// routeRecorder is implemented by modules recording the routes they mount.
+	routeRecorder interface {
+		mountedRoutes() []mountedRoute
+	}
+)
+
+type middlewareOption func(*middlewareEntry)
+
+// WithPriority sets the priority of a global middleware; lower priorities run first.
+// Middlewares of equal priority run in registration order. Defaults to 0.
+func WithPriority(priority int) middlewareOption {
+
+	return func(e *middlewareEntry) {
+
+		e.priority = priority
+	}
+}
+
+// RunBefore makes a global middleware run before the named ones, whatever their priority.
+// Names that are not registered are ignored.
+func RunBefore(names ...string) middlewareOption {
+
+	return func(e *middlewareEntry) {
+
+		e.before = append(e.before, names...)
+	}
+}
+
+// RunAfter makes a global middleware run after the named ones, whatever their priority.
+// Names that are not registered are ignored.
+func RunAfter(names ...string) middlewareOption {
+
+	return func(e *middlewareEntry) {
+
+		e.after = append(e.after, names...)
+	}
+}
+
+// WithExcludePaths skips a global middleware for requests to the given paths.
+// A path ending with "*" excludes every path starting with what precedes it,
+// e.g. "/internal/*".
+func WithExcludePaths(paths ...string) middlewareOption {
+
+	return func(e *middlewareEntry) {
+
+		e.exclude = append(e.exclude, paths...)
+	}
+}
+
+// Use registers a global middleware under a unique name. Global middlewares run for
+// every route, before module middlewares, in the order set by WithPriority, RunBefore
+// and RunAfter. They are applied when the modules are initialized, whenever Use is
+// called before that.
+func (server *runner) Use(name string, mw gin.HandlerFunc, opts ...middlewareOption) error {
+
+	if name == "" || mw == nil {
+
+		return errors.New("global middleware needs a name and a handler")
+	}
+
+	if server.modulesInitialized {
+
+		return fmt.Errorf("cannot use middleware %q :: modules are already initialized", name)
+	}
+
+	for _, e := range server.middlewares {
+
+		if e.name == name {
+
+			return fmt.Errorf("middleware %q is already registered", name)
+		}
+	}
+
+	entry := &middlewareEntry{name: name, handler: mw, seq: server.middlewareSeq}
+	for _, opt := range opts {
+
+		opt(entry)
+	}
+
+	server.middlewareSeq++
+	server.middlewares = append(server.middlewares, entry)
+
+	return nil
+}
+
+// unuse removes the global middleware registered under name, if any.
+func (server *runner) unuse(name string) {
+
+	for i, e := range server.middlewares {
+
+		if e.name == name {
+
+			server.middlewares = append(server.middlewares[:i], server.middlewares[i+1:]...)
+			return
+		}
+	}
+}
+
+// Middlewares returns the names of the global middlewares in the order they run.
+func (server *runner) Middlewares() ([]string, error) {
+
+	ordered, err := server.orderedMiddlewares()
+	if err != nil {
+
+		return nil, err
+	}
+
+	names := make([]string, len(ordered))
+	for i, e := range ordered {
+
+		names[i] = e.name
+	}
+
+	return names, nil
+}
+
+// Chain returns the names of the handlers run, in order, for the route registered with
+// method and path, the Gin path pattern (e.g., "/users/:id"). Global middlewares are
+// listed by name, other handlers by function name. Modules must be initialized.
+// For routes not mounted by a SolaModule, only global middlewares and the handler are known.
+func (server *runner) Chain(method, path string) ([]string, error) {
+
+	if !server.modulesInitialized {
+
+		return nil, errors.New("modules are not initialized")
+	}
+
+	global := server.engineChain
+	for _, m := range server.allModules() {
+
+		recorder, ok := m.(routeRecorder)
+		if !ok {
+
+			continue
+		}
+
+		for _, route := range recorder.mountedRoutes() {
+
+			if route.method != method || route.path != path {
+
+				continue
+			}
+
+			names := append([]string{}, global...)
+			for i := len(global); i < len(route.handlers); i++ {
+
+				names = append(names, handlerName(route.handlers[i]))
+			}
+
+			return names, nil
+		}
+	}
+
+	for _, route := range server.Engine.Routes() {
+
+		if route.Method == method && route.Path == path {
+
+			return append(append([]string{}, global...), route.Handler), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no route %s %s", method, path)
+}
+
+// applyMiddlewares adds the global middlewares to the engine, in order, and records
+// the names of the engine's handlers. It falls back to priority order if the
+// ordering constraints cannot be satisfied; Start reports that case as an error.
+func (server *runner) applyMiddlewares() {
+
+	ordered, err := server.orderedMiddlewares()
+	if err != nil {
+
+		server.Logger().Warn("using priority order for global middlewares", "error", err)
+		ordered = server.prioritizedMiddlewares()
+	}
+
+	// Handlers added directly through GinEngine().Use run first
+	server.engineChain = server.engineChain[:0]
+	for _, h := range server.Engine.Handlers {
+
+		server.engineChain = append(server.engineChain, handlerName(h))
+	}
+
+	for _, e := range ordered {
+
+		server.Engine.Use(e.wrap())
+		server.engineChain = append(server.engineChain, e.name)
+	}
+}
+
+// prioritizedMiddlewares returns the global middlewares sorted by priority,
+// then registration order.
+func (server *runner) prioritizedMiddlewares() []*middlewareEntry {
+
+	ordered := append([]*middlewareEntry{}, server.middlewares...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+
+		return ordered[i].runsFirst(ordered[j])
+	})
+
+	return ordered
+}
+
+// orderedMiddlewares returns the global middlewares in the order they run: each after
+// the ones it must run after, and otherwise by priority, then registration order.
+// It returns an error if RunBefore and RunAfter constraints form a cycle.
+func (server *runner) orderedMiddlewares() ([]*middlewareEntry, error) {
+
+	byName := make(map[string]*middlewareEntry, len(server.middlewares))
+	for _, e := range server.middlewares {
+
+		byName[e.name] = e
+	}
+
+	// next[a] lists the middlewares that must run after a
+	next := make(map[*middlewareEntry][]*middlewareEntry)
+	pending := make(map[*middlewareEntry]int)
+	for _, e := range server.middlewares {
+
+		for _, name := range e.before {
+
+			if other, ok := byName[name]; ok {
+
+				next[e] = append(next[e], other)
+				pending[other]++
+			}
+		}
+
+		for _, name := range e.after {
+
+			if other, ok := byName[name]; ok {
+
+				next[other] = append(next[other], e)
+				pending[e]++
+			}
+		}
+	}
+
+	ordered := make([]*middlewareEntry, 0, len(server.middlewares))
+	ready := make([]*middlewareEntry, 0, len(server.middlewares))
+	for _, e := range server.middlewares {
+
+		if pending[e] == 0 {
+
+			ready = append(ready, e)
+		}
+	}
+
+	for len(ready) > 0 {
+
+		// Take the ready middleware that runs first
+		first := 0
+		for i := range ready {
+
+			if ready[i].runsFirst(ready[first]) {
+
+				first = i
+			}
+		}
+
+		e := ready[first]
+		ready = append(ready[:first], ready[first+1:]...)
+		ordered = append(ordered, e)
+
+		for _, other := range next[e] {
+
+			if pending[other]--; pending[other] == 0 {
+
+				ready = append(ready, other)
+			}
+		}
+	}
+
+	if len(ordered) < len(server.middlewares) {
+
+		var stuck []string
+		for _, e := range server.middlewares {
+
+			if pending[e] > 0 {
+
+				stuck = append(stuck, e.name)
+			}
+		}
+
+		return nil, fmt.Errorf("middleware ordering cycle between %s", strings.Join(stuck, ", "))
+	}
+
+	return ordered, nil
+}
+
+// runsFirst reports whether e runs before other, by priority, then registration order.
+func (e *middlewareEntry) runsFirst(other *middlewareEntry) bool {
+
+	if e.priority != other.priority {
+
+		return e.priority < other.priority
+	}
+
+	return e.seq < other.seq
+}
+
+// wrap returns the handler of the middleware, skipping it for excluded paths.
+func (e *middlewareEntry) wrap() gin.HandlerFunc {
+
+	if len(e.exclude) == 0 {
+
+		return e.handler
+	}
+
+	return func(c *gin.Context) {
+
+		if excluded(c.Request.URL.Path, e.exclude) {
+
+			c.Next()
+			return
+		}
+
+		e.handler(c)
+	}
+}
+
+// excluded reports whether a request path matches one of the excluded paths.
+func excluded(path string, exclude []string) bool {
+
+	for _, pattern := range exclude {
+
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+
+			if strings.HasPrefix(path, prefix) {
+
+				return true
+			}
+		} else if path == pattern {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// mountedRoutes returns the routes the module mounted, with their complete handler chains.
+func (m *SolaModule) mountedRoutes() []mountedRoute {
+
+	return m.routes
+}
+
+// handlerName returns the function name of a handler, the way Gin names handlers.
+func handlerName(h gin.HandlerFunc) string {
+
+	return runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+}
@@ -0,0 +1,505 @@
+package solanum
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification documents are generated for.
+const OpenAPIVersion = "3.1.0"
+
+type (
+	// OpenAPIDocument is the root object of an OpenAPI document.
+	OpenAPIDocument struct {
+		OpenAPI    string                                  `json:"openapi"`
+		Info       OpenAPIInfo                             `json:"info"`
+		Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+		Components *OpenAPIComponents                      `json:"components,omitempty"`
+	}
+
+	// OpenAPIInfo describes the API in the info object of the document.
+	OpenAPIInfo struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// OpenAPIOperation describes a single route, keyed by its lower-case HTTP method.
+	OpenAPIOperation struct {
+		OperationID string                      `json:"operationId,omitempty"`
+		Summary     string                      `json:"summary,omitempty"`
+		Tags        []string                    `json:"tags,omitempty"`
+		Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+		RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*OpenAPIResponse `json:"responses"`
+	}
+
+	// OpenAPIParameter describes a path, query or header parameter of an operation.
+	OpenAPIParameter struct {
+		Name     string         `json:"name"`
+		In       string         `json:"in"`
+		Required bool           `json:"required,omitempty"`
+		Schema   *OpenAPISchema `json:"schema"`
+	}
+
+	// OpenAPIRequestBody describes the body of an operation's request.
+	OpenAPIRequestBody struct {
+		Required bool                         `json:"required,omitempty"`
+		Content  map[string]*OpenAPIMediaType `json:"content"`
+	}
+
+	// OpenAPIResponse describes a response of an operation, keyed by its status code.
+	OpenAPIResponse struct {
+		Description string                       `json:"description"`
+		Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+	}
+
+	// OpenAPIMediaType holds the schema of a request or response body for one media type.
+	OpenAPIMediaType struct {
+		Schema *OpenAPISchema `json:"schema"`
+	}
+
+	// OpenAPIComponents holds the named schemas referenced throughout the document.
+	OpenAPIComponents struct {
+		Schemas map[string]*OpenAPISchema `json:"schemas,omitempty"`
+	}
+
+	// OpenAPISchema is the subset of JSON Schema generated from Go types.
+	// The zero value is the empty schema, which accepts any value.
+	OpenAPISchema struct {
+		Ref                  string                    `json:"$ref,omitempty"`
+		Type                 string                    `json:"type,omitempty"`
+		Format               string                    `json:"format,omitempty"`
+		Items                *OpenAPISchema            `json:"items,omitempty"`
+		Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+		Required             []string                  `json:"required,omitempty"`
+		AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	}
+
+	// openAPIBuilder generates schemas, collecting named struct types as components.
+	openAPIBuilder struct {
+		schemas map[string]*OpenAPISchema // component schemas by name
+		names   map[reflect.Type]string   // component name of each struct type seen
+	}
+)
+
+// parameterTags maps the struct tags that bind request parameters to their OpenAPI location.
+var parameterTags = []struct{ tag, in string }{
+	{"uri", "path"},
+	{"form", "query"},
+	{"header", "header"},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// OpenAPI builds an OpenAPI 3.1 document from the routes of the registered modules,
+// their sub-modules, controllers and services. Path parameters such as /:id become {id};
+// MetaName, MetaSummary, MetaTags and MetaStatus fill the operations; SolaService.Request and
+// SolaService.Response describe parameters, request and response bodies.
+func (server *runner) OpenAPI() *OpenAPIDocument {
+
+	info := server.openAPIInfo
+	if info.Title == "" {
+
+		info.Title = "Solanum"
+	}
+
+	if info.Version == "" {
+
+		info.Version = "0.0.0"
+	}
+
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+
+	b := &openAPIBuilder{
+		schemas: make(map[string]*OpenAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+
+	for _, route := range server.Routes() {
+
+		p, params := openAPIPath(route.Path)
+		if doc.Paths[p] == nil {
+
+			doc.Paths[p] = make(map[string]*OpenAPIOperation)
+		}
+
+		doc.Paths[p][strings.ToLower(route.Method)] = b.operation(route.Service, params)
+	}
+
+	if len(b.schemas) > 0 {
+
+		doc.Components = &OpenAPIComponents{Schemas: b.schemas}
+	}
+
+	return doc
+}
+
+// serveOpenAPI serves the OpenAPI document of the mounted modules at the configured path.
+func (server *runner) serveOpenAPI() {
+
+	doc := server.OpenAPI()
+	server.GinEngine().GET(server.openAPIPath, func(c *gin.Context) {
+
+		c.JSON(http.StatusOK, doc)
+	})
+}
+
+// WithOpenAPI serves an OpenAPI 3.1 document describing the registered modules at path
+// (e.g., "/openapi.json"), once the modules are initialized.
+func WithOpenAPI(path string, info OpenAPIInfo) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.openAPIPath = path
+			runner.openAPIInfo = info
+		} else {
+
+			slog.Default().Warn("unable to set OpenAPI: Runner is not of type *runner")
+		}
+	}
+}
+
+// openAPIPath converts a Gin path into an OpenAPI path, turning /:id and /*path
+// into /{id} and /{path}. It also returns the names of the path parameters.
+func openAPIPath(ginPath string) (string, []string) {
+
+	segments := strings.Split(ginPath, "/")
+	var params []string
+
+	for i, segment := range segments {
+
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// operation describes svc, mounted on a path with the given path parameters.
+func (b *openAPIBuilder) operation(svc *SolaService, pathParams []string) *OpenAPIOperation {
+
+	op := &OpenAPIOperation{
+		Responses: make(map[string]*OpenAPIResponse),
+	}
+
+	if name, ok := svc.Meta[MetaName].(string); ok {
+
+		op.OperationID = name
+	}
+
+	if summary, ok := svc.Meta[MetaSummary].(string); ok {
+
+		op.Summary = summary
+	}
+
+	if tags, ok := svc.Meta[MetaTags].([]string); ok {
+
+		op.Tags = tags
+	}
+
+	var declared []*OpenAPIParameter
+	var body *OpenAPISchema
+	if svc.Request != nil {
+
+		declared, body = b.request(reflect.TypeOf(svc.Request))
+	}
+
+	// Every path parameter is documented, as a string unless the request type declares it
+	for _, name := range pathParams {
+
+		param := &OpenAPIParameter{Name: name, In: "path", Required: true, Schema: &OpenAPISchema{Type: "string"}}
+		for _, d := range declared {
+
+			if d.In == "path" && d.Name == name {
+
+				param.Schema = d.Schema
+			}
+		}
+
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	for _, d := range declared {
+
+		if d.In != "path" {
+
+			op.Parameters = append(op.Parameters, d)
+		}
+	}
+
+	if body != nil {
+
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  map[string]*OpenAPIMediaType{gin.MIMEJSON: {Schema: body}},
+		}
+	}
+
+	status := http.StatusOK
+	if s, ok := svc.Meta[MetaStatus].(int); ok {
+
+		status = s
+	}
+
+	response := &OpenAPIResponse{Description: http.StatusText(status)}
+	if svc.Response != nil && status != http.StatusNoContent {
+
+		response.Content = map[string]*OpenAPIMediaType{
+			gin.MIMEJSON: {Schema: b.schema(reflect.TypeOf(svc.Response))},
+		}
+	}
+	op.Responses[fmt.Sprint(status)] = response
+
+	return op
+}
+
+// request splits a request type into its parameters and its body schema. Struct fields
+// tagged uri, form or header are parameters; the remaining fields make up the body.
+// The body schema is nil if no field is left for it.
+func (b *openAPIBuilder) request(t reflect.Type) ([]*OpenAPIParameter, *OpenAPISchema) {
+
+	t = indirect(t)
+	if t.Kind() != reflect.Struct || t == timeType {
+
+		return nil, b.schema(t)
+	}
+
+	var params []*OpenAPIParameter
+	hasBody := false
+	for _, f := range fields(t) {
+
+		if p := parameter(f); p != nil {
+
+			p.Schema = b.schema(f.Type)
+			params = append(params, p)
+		} else if _, ok := jsonName(f); ok {
+
+			hasBody = true
+		}
+	}
+
+	switch {
+	case !hasBody:
+		return params, nil
+	case len(params) == 0:
+		return params, b.schema(t)
+	default:
+		// The body is only part of the type, so it cannot reference the type's component
+		return params, b.object(t, true)
+	}
+}
+
+// schema returns the schema of t, referencing named struct types as components.
+func (b *openAPIBuilder) schema(t reflect.Type) *OpenAPISchema {
+
+	t = indirect(t)
+	if t == timeType {
+
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+
+			// encoding/json renders byte slices as base64 strings
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+
+		return &OpenAPISchema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+
+			return b.object(t, false)
+		}
+
+		return &OpenAPISchema{Ref: "#/components/schemas/" + b.component(t)}
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+// component registers the schema of a named struct type, once, and returns its name.
+func (b *openAPIBuilder) component(t reflect.Type) string {
+
+	if name, ok := b.names[t]; ok {
+
+		return name
+	}
+
+	name := componentName(t)
+	for i := 2; b.schemas[name] != nil; i++ {
+
+		name = fmt.Sprintf("%s%d", componentName(t), i)
+	}
+
+	// Register before generating, so that recursive types reference themselves
+	s := &OpenAPISchema{}
+	b.names[t] = name
+	b.schemas[name] = s
+	*s = *b.object(t, false)
+
+	return name
+}
+
+// object returns the inline object schema of a struct type, listing the fields
+// encoding/json would marshal. With bodyOnly, parameter fields are left out.
+func (b *openAPIBuilder) object(t reflect.Type, bodyOnly bool) *OpenAPISchema {
+
+	s := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	for _, f := range fields(t) {
+
+		if bodyOnly && parameter(f) != nil {
+
+			continue
+		}
+
+		name, ok := jsonName(f)
+		if !ok {
+
+			continue
+		}
+
+		s.Properties[name] = b.schema(f.Type)
+		if required(f) {
+
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s
+}
+
+// fields returns the exported fields of a struct type, with the fields of embedded
+// structs without a JSON name promoted, the way encoding/json does.
+func fields(t reflect.Type) []reflect.StructField {
+
+	var fs []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+		if f.Anonymous && f.Tag.Get("json") == "" && indirect(f.Type).Kind() == reflect.Struct {
+
+			fs = append(fs, fields(indirect(f.Type))...)
+			continue
+		}
+
+		if f.IsExported() {
+
+			fs = append(fs, f)
+		}
+	}
+
+	return fs
+}
+
+// jsonName returns the JSON name of a struct field, or false if encoding/json skips it.
+func jsonName(f reflect.StructField) (string, bool) {
+
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+
+		return name, true
+	}
+
+	return f.Name, true
+}
+
+// parameter returns the parameter a struct field binds, without its schema,
+// or nil if the field has no uri, form or header tag.
+func parameter(f reflect.StructField) *OpenAPIParameter {
+
+	for _, pt := range parameterTags {
+
+		tag, ok := f.Tag.Lookup(pt.tag)
+		if !ok || tag == "-" {
+
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+
+			name = f.Name
+		}
+
+		return &OpenAPIParameter{Name: name, In: pt.in, Required: pt.in == "path" || required(f)}
+	}
+
+	return nil
+}
+
+// required reports whether a struct field is marked required by its binding tag.
+func required(f reflect.StructField) bool {
+
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+
+		if rule == "required" {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// componentName derives a component name from a type name, replacing the characters
+// OpenAPI does not allow in component names, such as those of generic type arguments.
+func componentName(t reflect.Type) string {
+
+	return strings.Map(func(r rune) rune {
+
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, t.Name())
+}
+
+// indirect dereferences pointer types.
+func indirect(t reflect.Type) reflect.Type {
+
+	for t.Kind() == reflect.Pointer {
+
+		t = t.Elem()
+	}
+
+	return t
+}
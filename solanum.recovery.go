@@ -0,0 +1,134 @@
+package solanum
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery returns a middleware recovering from panics in the rest of the chain.
+// It logs the panic with slog.Default(), along with its stack trace, the request ID
+// and the route, then calls handler, unless the client connection is broken, in which
+// case the request is only aborted. A nil handler aborts the request with a 500 *Error,
+// which ErrorHandler renders as problem+json.
+func Recovery(handler gin.RecoveryFunc) gin.HandlerFunc {
+
+	return recovery(func() gin.RecoveryFunc { return handler }, slog.Default)
+}
+
+// recovery is Recovery, reading the handler and the logger for every panic.
+func recovery(handler func() gin.RecoveryFunc, logger func() *slog.Logger) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		defer func() {
+
+			recovered := recover()
+			if recovered == nil {
+
+				return
+			}
+
+			route := c.FullPath()
+			if route == "" {
+
+				route = c.Request.URL.Path
+			}
+
+			if brokenPipe(recovered) {
+
+				logger().WarnContext(
+					c.Request.Context(),
+					"connection broken",
+					"error", recovered,
+					"method", c.Request.Method,
+					"route", route,
+					"request_id", requestIDOf(c),
+				)
+
+				_ = c.Error(fmt.Errorf("connection broken :: %v", recovered))
+				c.Abort()
+				return
+			}
+
+			logger().ErrorContext(
+				c.Request.Context(),
+				"panic recovered",
+				"panic", recovered,
+				"method", c.Request.Method,
+				"route", route,
+				"request_id", requestIDOf(c),
+				"stack", string(debug.Stack()),
+			)
+
+			if h := handler(); h != nil {
+
+				h(c, recovered)
+				return
+			}
+
+			// Already logged along with the stack trace; ErrorHandler only renders it
+			c.Error(&Error{
+				Status: http.StatusInternalServerError,
+				Err:    fmt.Errorf("panic recovered :: %v", recovered),
+			}).SetMeta(errorLogged)
+			c.Status(http.StatusInternalServerError)
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
+
+// brokenPipe reports whether a recovered value is a write error of a connection
+// closed by the client, to which no response can be written.
+func brokenPipe(recovered any) bool {
+
+	err, ok := recovered.(error)
+	if !ok {
+
+		return false
+	}
+
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+
+		var sysErr *os.SyscallError
+		if errors.As(opErr, &sysErr) {
+
+			msg := strings.ToLower(sysErr.Error())
+			return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+		}
+	}
+
+	return false
+}
+
+// WithRecovery replaces the handler Recovery calls after logging a panic, e.g. to
+// render a custom body. The handler is responsible for aborting the request.
+func WithRecovery(handler gin.RecoveryFunc) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.recovery = handler
+		} else {
+
+			slog.Default().Warn("unable to set recovery: Runner is not of type *runner")
+		}
+	}
+}
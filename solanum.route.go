@@ -0,0 +1,174 @@
+package solanum
+
+import (
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Well-known SolaService.Meta keys. Meta is free-form; these keys are the ones
+// Solanum's own subsystems understand.
+const (
+	// MetaName a unique, human-readable name for the service (string)
+	MetaName = "name"
+
+	// MetaSummary a one-line description of the service (string)
+	MetaSummary = "summary"
+
+	// MetaTags labels used to group services ([]string)
+	MetaTags = "tags"
+
+	// MetaRoles roles a caller needs to use the service ([]string)
+	MetaRoles = "roles"
+
+	// MetaStatus the status code of a successful response (int), 200 if unset
+	MetaStatus = "status"
+)
+
+// serviceContextKey is the gin.Context key under which the current SolaService is stored.
+const serviceContextKey = "solanum.service"
+
+// RouteInfo describes a route mounted by a module, as returned by Runner.Routes.
+type RouteInfo struct {
+	// Method the HTTP method of the route
+	Method string
+
+	// Path the full Gin path of the route, including module URI and controller prefix
+	Path string
+
+	// Module the module that mounts the route
+	Module Module
+
+	// ModulePath the full path the module is mounted at, including parent module URIs
+	ModulePath string
+
+	// Controller the controller that owns the service
+	Controller Controller
+
+	// Service the service handling the route, including its Meta
+	Service *SolaService
+}
+
+// ServiceFromContext returns the SolaService handling the current request, or nil
+// if the request is not handled by a module route. It is available to the service's
+// whole chain, including pre- and post-middleware, e.g. to check MetaRoles.
+func ServiceFromContext(c *gin.Context) *SolaService {
+
+	if v, ok := c.Get(serviceContextKey); ok {
+
+		if svc, ok := v.(*SolaService); ok {
+
+			return svc
+		}
+	}
+
+	return nil
+}
+
+// serviceContext returns a middleware exposing svc through ServiceFromContext.
+func serviceContext(svc *SolaService) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		c.Set(serviceContextKey, svc)
+		c.Next()
+	}
+}
+
+// Routes returns every route of the registered modules, in registration order,
+// with the same paths InitModules mounts them on.
+func (server *runner) Routes() []RouteInfo {
+
+	routes := make([]RouteInfo, 0)
+	for _, m := range server.modules {
+
+		routes = append(routes, moduleRoutes(*m, joinPaths("/", (*m).Uri()))...)
+	}
+
+	return routes
+}
+
+// moduleRoutes lists the routes of module m mounted at basePath.
+func moduleRoutes(m Module, basePath string) []RouteInfo {
+
+	routes := make([]RouteInfo, 0)
+	for _, c := range m.Controllers() {
+
+		ctrPath := basePath
+		if rc, ok := c.(RoutedController); ok {
+
+			ctrPath = joinPaths(basePath, rc.Prefix())
+		}
+
+		for _, svc := range c.Handlers() {
+
+			if svc == nil {
+
+				continue
+			}
+
+			routes = append(routes, RouteInfo{
+				Method:     svc.Method,
+				Path:       joinPaths(ctrPath, svc.Uri),
+				Module:     m,
+				ModulePath: basePath,
+				Controller: c,
+				Service:    svc,
+			})
+		}
+	}
+
+	if parent, ok := m.(ParentModule); ok {
+
+		for _, sub := range parent.SubModules() {
+
+			routes = append(routes, moduleRoutes(sub, joinPaths(basePath, sub.Uri()))...)
+		}
+	}
+
+	return routes
+}
+
+// allModules returns the registered modules and, depth-first, their sub-modules.
+func (server *runner) allModules() []Module {
+
+	var modules []Module
+
+	var walk func(m Module)
+	walk = func(m Module) {
+
+		modules = append(modules, m)
+		if parent, ok := m.(ParentModule); ok {
+
+			for _, sub := range parent.SubModules() {
+
+				walk(sub)
+			}
+		}
+	}
+
+	for _, m := range server.modules {
+
+		walk(*m)
+	}
+
+	return modules
+}
+
+// joinPaths joins a relative path onto an absolute one the way Gin router groups do,
+// keeping a trailing slash of the relative path.
+func joinPaths(absolutePath, relativePath string) string {
+
+	if relativePath == "" {
+
+		return absolutePath
+	}
+
+	finalPath := path.Join(absolutePath, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && finalPath[len(finalPath)-1] != '/' {
+
+		return finalPath + "/"
+	}
+
+	return finalPath
+}
@@ -0,0 +1,447 @@
+package solanum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/annuums/solanum/container"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultShutdownTimeout bounds how long Shutdown waits for in-flight requests to drain.
+	DefaultShutdownTimeout = 10 * time.Second
+
+	// DefaultStopTimeout bounds how long Shutdown waits for stop hooks and the shutdown
+	// of containers.
+	DefaultStopTimeout = 10 * time.Second
+)
+
+// Run starts the HTTP server and blocks until SIGINT or SIGTERM is received or the
+// server fails, then shuts down gracefully. It returns the serve error, if any,
+// joined with any error from Shutdown.
+func (server *runner) Run() error {
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := server.Start(ctx); err != nil {
+
+		return err
+	}
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		server.Logger().Info("shutting down")
+	case serveErr = <-server.serveErr:
+	}
+
+	// Restore default signal handling, so a second signal kills the process
+	stop()
+
+	return errors.Join(serveErr, server.Shutdown(context.Background()))
+}
+
+// Start validates dependencies, initializes modules and starts serving HTTP in the
+// background: on the configured host and port, or a random port for port 0, unless
+// WithUnixSocket or WithListener is set, and over TLS if WithTLS or WithTLSConfig is.
+// It returns once the listener is bound, or with the error that prevented it; Addr then
+// reports the bound address. Use Shutdown to stop the server.
+func (server *runner) Start(ctx context.Context) error {
+
+	// Hold the lock only to claim the start, so that hooks can call Addr or Shutdown
+	server.mu.Lock()
+	if server.httpServer != nil || server.starting {
+
+		server.mu.Unlock()
+		return errors.New("server is already running")
+	}
+
+	server.starting = true
+	server.mu.Unlock()
+
+	defer func() {
+
+		server.mu.Lock()
+		server.starting = false
+		server.mu.Unlock()
+	}()
+
+	if server.port == nil && server.listener == nil && server.unixSocket == "" {
+
+		return errors.New("server port is not configured, please set a port before running")
+	}
+
+	if server.Engine == nil {
+
+		return errors.New("server engine is not configured")
+	}
+
+	if err := server.ValidateDependencies(); err != nil {
+
+		return fmt.Errorf("dependency check failed :: %w", err)
+	}
+
+	if !server.modulesInitialized {
+
+		if _, err := server.orderedMiddlewares(); err != nil {
+
+			return fmt.Errorf("global middleware check failed :: %w", err)
+		}
+
+		server.InitModules()
+	}
+
+	tlsConfig, err := server.serverTLSConfig()
+	if err != nil {
+
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           server.Engine.Handler(),
+		TLSConfig:         tlsConfig,
+		ReadTimeout:       server.readTimeout,
+		ReadHeaderTimeout: server.readHeaderTimeoutOrDefault(),
+		WriteTimeout:      server.writeTimeout,
+		IdleTimeout:       server.idleTimeout,
+		MaxHeaderBytes:    server.maxHeaderBytes,
+	}
+
+	var h2cRequests *sync.WaitGroup
+	if server.h2c {
+
+		h2cRequests = &sync.WaitGroup{}
+		if err := serveH2C(srv, h2cRequests); err != nil {
+
+			return err
+		}
+	}
+
+	server.draining.Store(false)
+	if err := server.startHooks(ctx); err != nil {
+
+		return fmt.Errorf("startup aborted :: %w", err)
+	}
+
+	ln, err := server.listen(ctx)
+	if err != nil {
+
+		return errors.Join(err, server.stopHooks(ctx))
+	}
+
+	addr := ln.Addr().String()
+	srv.Addr = addr
+
+	serveErr := make(chan error, 1)
+
+	server.mu.Lock()
+	server.httpServer = srv
+	server.h2cRequests = h2cRequests
+	server.serveErr = serveErr
+	server.addr = ln.Addr()
+	server.mu.Unlock()
+
+	go func() {
+
+		var err error
+		if tlsConfig != nil {
+
+			// The certificates are in the config already
+			err = srv.ServeTLS(ln, "", "")
+		} else {
+
+			err = srv.Serve(ln)
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+
+			serveErr <- fmt.Errorf("fail to serve on addr :: %s :: %w", addr, err)
+		}
+
+		close(serveErr)
+	}()
+
+	server.Logger().Info(
+		"solanum is running",
+		"addr", addr,
+		"network", ln.Addr().Network(),
+		"tls", tlsConfig != nil,
+		"h2c", server.h2c,
+	)
+
+	return nil
+}
+
+// Addr returns the address the server is bound to, e.g. to learn the random port
+// bound for port 0, or nil if the server is not running.
+func (server *runner) Addr() net.Addr {
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.addr
+}
+
+// Shutdown fails readiness checks from then on and, after the drain delay, stops the
+// HTTP server, if it is running, waiting up to the configured drain timeout for
+// in-flight requests to complete before closing remaining connections. It then runs
+// the stop hooks of modules and providers in reverse start order, and releases the
+// application's resources: it shuts down the container of every module that has its
+// own, then the runner's container, closing singletons in reverse dependency order.
+// Stop hooks and container shutdown get a context that expires after the configured
+// stop timeout. All errors are joined together.
+func (server *runner) Shutdown(ctx context.Context) error {
+
+	server.draining.Store(true)
+
+	server.mu.Lock()
+	srv := server.httpServer
+	h2cRequests := server.h2cRequests
+	server.httpServer = nil
+	server.h2cRequests = nil
+	server.mu.Unlock()
+
+	var errs []error
+	if srv != nil {
+
+		// Keep serving while load balancers notice the server is no longer ready
+		if server.drainDelay > 0 {
+
+			select {
+			case <-time.After(server.drainDelay):
+			case <-ctx.Done():
+			}
+		}
+
+		drainCtx, cancel := context.WithTimeout(ctx, server.drainTimeout())
+		defer cancel()
+
+		if err := srv.Shutdown(drainCtx); err != nil {
+
+			// Draining did not finish in time; drop the remaining connections
+			errs = append(errs, fmt.Errorf("graceful shutdown failed :: %w", err), srv.Close())
+		} else if err := waitH2C(drainCtx, h2cRequests); err != nil {
+
+			errs = append(errs, fmt.Errorf("graceful shutdown of h2c connections failed :: %w", err))
+		}
+
+		server.mu.Lock()
+		server.addr = nil
+		server.mu.Unlock()
+	}
+
+	// Bounded even for Run, whose shutdown context never expires
+	stopCtx, cancel := context.WithTimeout(ctx, server.stopTimeoutOrDefault())
+	defer cancel()
+
+	errs = append(errs, server.stopHooks(stopCtx), server.shutdownContainers(stopCtx))
+
+	return errors.Join(errs...)
+}
+
+// startHooks runs the start hooks of the providers of every container in use, the
+// runner's first, and then the OnStart hook of every module in registration order,
+// each parent module before its sub-modules.
+// On failure, whatever was started is stopped again.
+func (server *runner) startHooks(ctx context.Context) error {
+
+	for _, cont := range server.containers() {
+
+		if err := cont.Start(ctx); err != nil {
+
+			return errors.Join(err, server.stopHooks(ctx))
+		}
+
+		server.startedContainers = append(server.startedContainers, cont)
+	}
+
+	for _, m := range server.allModules() {
+
+		if starter, ok := m.(ModuleStarter); ok {
+
+			if err := starter.OnStart(ctx); err != nil {
+
+				return errors.Join(
+					fmt.Errorf("module %q failed to start :: %w", m.Uri(), err),
+					server.stopHooks(ctx),
+				)
+			}
+		}
+
+		server.startedModules = append(server.startedModules, m)
+	}
+
+	return nil
+}
+
+// stopHooks undoes startHooks: it runs the OnStop hook of every started module, then
+// the stop hooks of every started container, both in reverse start order.
+func (server *runner) stopHooks(ctx context.Context) error {
+
+	modules, containers := server.startedModules, server.startedContainers
+	server.startedModules, server.startedContainers = nil, nil
+
+	var errs []error
+	for i := len(modules) - 1; i >= 0; i-- {
+
+		if stopper, ok := modules[i].(ModuleStopper); ok {
+
+			if err := stopper.OnStop(ctx); err != nil {
+
+				errs = append(errs, fmt.Errorf("module %q failed to stop :: %w", modules[i].Uri(), err))
+			}
+		}
+	}
+
+	for i := len(containers) - 1; i >= 0; i-- {
+
+		if err := containers[i].Stop(ctx); err != nil {
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// containers returns every container in use, the runner's first, followed by the
+// distinct containers of modules and sub-modules, in registration order.
+func (server *runner) containers() []*container.Container {
+
+	root := server.Container()
+	seen := map[*container.Container]struct{}{root: {}}
+	containers := []*container.Container{root}
+
+	for _, m := range server.allModules() {
+
+		holder, ok := m.(containerHolder)
+		if !ok {
+
+			continue
+		}
+
+		cont := holder.Container()
+		if _, dup := seen[cont]; dup {
+
+			continue
+		}
+
+		seen[cont] = struct{}{}
+		containers = append(containers, cont)
+	}
+
+	return containers
+}
+
+// readHeaderTimeoutOrDefault returns the configured read header timeout, or
+// DefaultReadHeaderTimeout; a negative timeout disables it.
+func (server *runner) readHeaderTimeoutOrDefault() time.Duration {
+
+	switch {
+	case server.readHeaderTimeout < 0:
+		return 0
+	case server.readHeaderTimeout == 0:
+		return DefaultReadHeaderTimeout
+	default:
+		return server.readHeaderTimeout
+	}
+}
+
+// drainTimeout returns the configured drain timeout, or DefaultShutdownTimeout.
+func (server *runner) drainTimeout() time.Duration {
+
+	if server.shutdownTimeout <= 0 {
+
+		return DefaultShutdownTimeout
+	}
+
+	return server.shutdownTimeout
+}
+
+// stopTimeoutOrDefault returns the configured stop timeout, or DefaultStopTimeout.
+func (server *runner) stopTimeoutOrDefault() time.Duration {
+
+	if server.stopTimeout <= 0 {
+
+		return DefaultStopTimeout
+	}
+
+	return server.stopTimeout
+}
+
+// shutdownContainers shuts down module containers first, as they may be children
+// of the runner's container, and the runner's container last.
+func (server *runner) shutdownContainers(ctx context.Context) error {
+
+	containers := server.containers()
+
+	var errs []error
+	for i := len(containers) - 1; i >= 0; i-- {
+
+		if err := containers[i].Shutdown(ctx); err != nil {
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithDrainDelay makes Shutdown keep serving requests for delay after readiness checks
+// start failing, before it stops accepting connections, giving load balancers time to
+// route requests elsewhere. Defaults to no delay.
+func WithDrainDelay(delay time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.drainDelay = delay
+		} else {
+
+			slog.Default().Warn("unable to set drain delay: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithShutdownTimeout sets how long Shutdown waits for in-flight requests to drain
+// before closing the remaining connections. Defaults to DefaultShutdownTimeout.
+func WithShutdownTimeout(timeout time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.shutdownTimeout = timeout
+		} else {
+
+			slog.Default().Warn("unable to set shutdown timeout: Runner is not of type *runner")
+		}
+	}
+}
+
+// WithStopTimeout sets how long Shutdown waits for the stop hooks of modules and
+// providers, and for containers to close their singletons, once the server stopped.
+// Hooks should return when their context is done. Defaults to DefaultStopTimeout.
+func WithStopTimeout(timeout time.Duration) option {
+
+	return func(r Runner) {
+
+		if runner, ok := r.(*runner); ok {
+
+			runner.stopTimeout = timeout
+		} else {
+
+			slog.Default().Warn("unable to set stop timeout: Runner is not of type *runner")
+		}
+	}
+}
@@ -2,10 +2,19 @@ package solanum
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"github.com/annuums/solanum/container"
+	"github.com/annuums/solanum/util"
 	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net"
+	"net/http"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type (
@@ -17,12 +26,21 @@ type (
 		preMiddlewares  []gin.HandlerFunc              // middleware to run before each handler
 		postMiddlewares []gin.HandlerFunc              // middleware to run after each handler
 		dependencies    *[]*container.DependencyConfig // dependencies to inject via DI middleware
+		container       *container.Container           // container to resolve dependencies from; nil inherits the runner's
+		onStart         []func(context.Context) error  // hooks run by OnStart, in order
+		onStop          []func(context.Context) error  // hooks run by OnStop, in reverse order
+		subModules      []Module                       // modules mounted below this module's URI
+		parent          *SolaModule                    // module this one is mounted under, if any
+		routes          []mountedRoute                 // routes mounted by the last SetRoutes, with their chains
+		maxBodyBytes    int64                          // request body limit overriding the runner's; 0 inherits it
 	}
 
 	// SolaController groups one or more SolaService handlers under a logical controller.
 	// It implements the Controller interface, managing a list of SolaService entries.
 	SolaController struct {
-		handlers []*SolaService // handlers service handlers defined for this controller
+		handlers    []*SolaService    // handlers service handlers defined for this controller
+		prefix      string            // sub-path mounted between the module URI and each handler's Uri
+		middlewares []gin.HandlerFunc // middleware to run before each handler of this controller
 	}
 
 	// SolaService represents a single HTTP route handler configuration.
@@ -36,14 +54,70 @@ type (
 
 		// Handler the Gin handler function to execute
 		Handler gin.HandlerFunc
+
+		// Middlewares run after the module's (and controller's) pre-middleware
+		// and before Handler, for this service only (e.g., auth for one endpoint)
+		Middlewares []gin.HandlerFunc
+
+		// Meta free-form metadata about the service, readable by other subsystems
+		// through ServiceFromContext or Runner.Routes (see the Meta* keys)
+		Meta map[string]any
+
+		// Request a value of the type the service reads from the request (e.g., CreateUser{}),
+		// used to document it. Fields tagged uri, form or header document parameters;
+		// the remaining fields document the JSON body
+		Request any
+
+		// Response a value of the type the service writes as JSON (e.g., User{}), used to document it
+		Response any
 	}
 
 	// runner implements the Runner interface and drives the application startup.
 	// It holds the Gin engine, listening port, and registered modules.
 	runner struct {
-		Engine  *gin.Engine // underlying Gin engine
-		port    *int        // TCP port to listen on
-		modules []*Module   // pointers to registered modules
+		Engine             *gin.Engine            // underlying Gin engine
+		port               *int                   // TCP port to listen on
+		modules            []*Module              // pointers to registered modules
+		container          *container.Container   // DI container for the application; nil means container.Default()
+		modulesInitialized bool                   // whether InitModules has mounted the modules
+		shutdownTimeout    time.Duration          // drain timeout for in-flight requests on Shutdown
+		stopTimeout        time.Duration          // timeout for stop hooks and container shutdown on Shutdown
+		startedContainers  []*container.Container // containers whose start hooks ran, in start order
+		startedModules     []Module               // modules whose OnStart hook ran, in start order
+		openAPIPath        string                 // path the OpenAPI document is served at; empty disables it
+		openAPIInfo        OpenAPIInfo            // info object of the OpenAPI document
+		debug              bool                   // whether problem responses expose underlying errors
+		recovery           gin.RecoveryFunc       // handles recovered panics; nil aborts with a 500 *Error
+		middlewares        []*middlewareEntry     // global middlewares registered with Use
+		middlewareSeq      int                    // registration counter of global middlewares
+		engineChain        []string               // names of the engine's handlers, once modules are initialized
+		logger             *slog.Logger           // logger of the runner; nil means slog.Default()
+		metrics            *Metrics               // metrics set with WithMetrics, if any
+		health             *healthConfig          // readiness configuration set with WithHealth, if any
+		drainDelay         time.Duration          // how long Shutdown fails readiness before draining
+		draining           atomic.Bool            // whether Shutdown has started
+		cors               *util.CorsOption       // CORS configuration set with Cors, if any
+		host               string                 // host the server listens on; empty means every interface
+		tlsCertFile        string                 // PEM certificate file set with WithTLS
+		tlsKeyFile         string                 // PEM private key file set with WithTLS
+		tlsConfig          *tls.Config            // TLS configuration set with WithTLSConfig
+		unixSocket         string                 // Unix socket path to serve on instead of TCP
+		listener           net.Listener           // listener set with WithListener, used as is
+		listenerUsed       bool                   // whether Start took the listener set with WithListener
+		readTimeout        time.Duration          // http.Server ReadTimeout
+		readHeaderTimeout  time.Duration          // http.Server ReadHeaderTimeout; 0 means DefaultReadHeaderTimeout
+		writeTimeout       time.Duration          // http.Server WriteTimeout
+		idleTimeout        time.Duration          // http.Server IdleTimeout
+		maxHeaderBytes     int                    // http.Server MaxHeaderBytes
+		maxBodyBytes       int64                  // request body limit; 0 means no limit
+		h2c                bool                   // whether cleartext HTTP/2 is served alongside HTTP/1.1
+
+		mu          sync.Mutex      // protects httpServer, h2cRequests, serveErr, addr and starting
+		httpServer  *http.Server    // running HTTP server, nil when stopped
+		h2cRequests *sync.WaitGroup // requests in flight on h2c connections, nil without h2c
+		serveErr    <-chan error    // receives the serve error, closed when serving stops
+		addr        net.Addr        // address the server is bound to, nil when stopped
+		starting    bool            // set while Start runs, so that it cannot run twice at once
 	}
 )
 
@@ -58,8 +132,47 @@ func WithUri(uri string) moduleOption {
 	}
 }
 
+// WithModuleContainer makes the module resolve its dependencies from c instead of
+// the runner's container. Apply it before any WithDependency option, which validates
+// against the module's container.
+func WithModuleContainer(c *container.Container) moduleOption {
+
+	return func(m *SolaModule) error {
+
+		if c == nil {
+
+			return fmt.Errorf("module container must not be nil")
+		}
+
+		m.container = c
+		return nil
+	}
+}
+
+// WithOnStart adds a hook that runs when the application starts, after the providers'
+// start hooks and before the server listens. A failing hook aborts startup.
+func WithOnStart(hook func(ctx context.Context) error) moduleOption {
+
+	return func(m *SolaModule) error {
+
+		m.onStart = append(m.onStart, hook)
+		return nil
+	}
+}
+
+// WithOnStop adds a hook that runs when the application shuts down, after the server
+// stopped serving requests and before the providers' stop hooks.
+func WithOnStop(hook func(ctx context.Context) error) moduleOption {
+
+	return func(m *SolaModule) error {
+
+		m.onStop = append(m.onStop, hook)
+		return nil
+	}
+}
+
 // WithDependency registers a DependencyConfig with the module, but only if
-// that key is actually registered in the global container and its type matches.
+// that key is actually registered in the module's container and its type matches.
 // Returns an error if the key is missing in the container or if the types don’t align.
 func WithDependency(dep *container.DependencyConfig) moduleOption {
 
@@ -83,11 +196,14 @@ func WithDependency(dep *container.DependencyConfig) moduleOption {
 		// and that it matches dep.Type (if dep.Type is non-nil).
 		// If dep.Type is an interface, use ResolveByType to enforce implementation.
 		// Otherwise, use Resolve and check AssignableTo (or Implemen ts) manually.
+		// Resolve inside a throwaway scope, so that scoped providers can be checked too.
+		ctx, scope := container.NewScope(context.Background())
+		defer func() { _ = scope.Dispose() }()
 
 		if dep.Type != nil && dep.Type.Kind() == reflect.Interface {
 
 			// Ensure an instance can be resolved and implements dep.Type
-			inst, err := container.ResolveByType(dep.Key, dep.Type)
+			inst, err := m.Container().ResolveByTypeContext(ctx, dep.Key, dep.Type)
 			if err != nil {
 
 				return fmt.Errorf(
@@ -108,7 +224,7 @@ func WithDependency(dep *container.DependencyConfig) moduleOption {
 		} else {
 
 			// dep.Type is nil or a concrete type. Attempt a plain Resolve.
-			inst, err := container.Resolve(dep.Key)
+			inst, err := m.Container().ResolveContext(ctx, dep.Key)
 			if err != nil {
 
 				return fmt.Errorf("cannot register dependency %q: key not found in container: %w", dep.Key, err)
@@ -224,48 +340,198 @@ func (m *SolaModule) SetDependencies(deps ...container.DependencyConfig) {
 	}
 }
 
+// OnStart runs the module's start hooks in the order they were added,
+// stopping at the first failure.
+func (m *SolaModule) OnStart(ctx context.Context) error {
+
+	for _, hook := range m.onStart {
+
+		if err := hook(ctx); err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnStop runs the module's stop hooks in reverse order and joins their errors.
+func (m *SolaModule) OnStop(ctx context.Context) error {
+
+	var errs []error
+	for i := len(m.onStop) - 1; i >= 0; i-- {
+
+		if err := m.onStop[i](ctx); err != nil {
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Container returns the container the module resolves its dependencies from.
+// Until one is set through WithModuleContainer or inherited from the runner in
+// SetModules, this is container.Default().
+func (m *SolaModule) Container() *container.Container {
+
+	if m.container == nil {
+
+		// Sub-modules resolve from their parent's container unless they have their own
+		if m.parent != nil {
+
+			return m.parent.Container()
+		}
+
+		return container.Default()
+	}
+
+	return m.container
+}
+
+// AddSubModules mounts one or more modules below this module's URI, e.g. "/users" and
+// "/orders" modules under an "/api/v1" module. Sub-modules inherit this module's
+// dependency injection and container, and their routes run inside this module's pre-
+// and post-middleware. They can declare dependencies of their own, which override
+// inherited ones with the same key, and have their own container and middleware.
+func (m *SolaModule) AddSubModules(subs ...Module) {
+
+	for _, sub := range subs {
+
+		if sm, ok := sub.(*SolaModule); ok {
+
+			sm.parent = m
+		}
+
+		m.subModules = append(m.subModules, sub)
+	}
+}
+
+// SubModules returns the modules mounted below this module.
+func (m *SolaModule) SubModules() []Module {
+
+	return m.subModules
+}
+
 // SetRoutes registers the module's routes, middleware, and DI middleware on the given RouterGroup.
-// It applies DI if dependencies are defined, then mounts each SolaService handler with pre- and post-middleware.
+// It applies DI if dependencies are defined, then mounts each SolaService handler of every
+// Controller with pre- and post-middleware. Controllers implementing RoutedController are
+// mounted under their Prefix, with their Middlewares run between pre-middleware and handler,
+// followed by the service's own Middlewares. The SolaService is exposed to the whole chain
+// through ServiceFromContext. Sub-modules are mounted below the module's routes.
 func (m *SolaModule) SetRoutes(router *gin.RouterGroup) {
 
-	// Apply DI middleware if dependencies exist
+	m.mount(router, nil, nil)
+}
+
+// mount registers the module's routes on router, wrapping its own pre- and post-middleware
+// in the ones inherited from parent modules, then mounts its sub-modules.
+func (m *SolaModule) mount(router *gin.RouterGroup, inheritedPre, inheritedPost []gin.HandlerFunc) {
+
+	m.routes = nil
+
+	// Apply DI middleware if dependencies exist; sub-module groups inherit it
 	if len(*m.dependencies) > 0 {
 
-		router.Use(diMiddleware(m.dependencies))
+		router.Use(diMiddleware(m.Container(), m.dependencies))
 	}
 
+	// parent pre → own pre ... own post → parent post
+	pre := make([]gin.HandlerFunc, 0, len(inheritedPre)+len(m.preMiddlewares))
+	pre = append(append(pre, inheritedPre...), m.preMiddlewares...)
+	post := make([]gin.HandlerFunc, 0, len(m.postMiddlewares)+len(inheritedPost))
+	post = append(append(post, m.postMiddlewares...), inheritedPost...)
+
 	// Iterate controllers and their services
 	for _, c := range m.controllers {
 
-		ctr, ok := c.(*SolaController)
-		if !ok {
+		group := router
+		var ctrMiddlewares []gin.HandlerFunc
+
+		// Controllers may contribute a sub-path and their own middleware
+		if rc, ok := c.(RoutedController); ok {
+
+			ctrMiddlewares = rc.Middlewares()
+			if prefix := rc.Prefix(); prefix != "" {
+
+				group = router.Group(prefix)
+			}
+		}
+
+		for _, svc := range c.Handlers() {
 
-			panic(fmt.Sprintf("controller is not *SolaController: %T", c))
+			if svc == nil {
+
+				continue
+			}
+
+			// service context → pre → controller → service → handler → post
+			chain := make([]gin.HandlerFunc, 0, len(pre)+len(ctrMiddlewares)+len(svc.Middlewares)+len(post)+2)
+			chain = append(chain, serviceContext(svc))
+			chain = append(chain, pre...)
+			chain = append(chain, ctrMiddlewares...)
+			chain = append(chain, svc.Middlewares...)
+			chain = append(chain, svc.Handler)
+			chain = append(chain, post...)
+
+			group.Handle(svc.Method, svc.Uri, chain...)
+
+			// Record the chain the way the group combines it, for Runner.Chain
+			handlers := make(gin.HandlersChain, 0, len(group.Handlers)+len(chain))
+			m.routes = append(m.routes, mountedRoute{
+				method:   svc.Method,
+				path:     joinPaths(group.BasePath(), svc.Uri),
+				handlers: append(append(handlers, group.Handlers...), chain...),
+			})
 		}
+	}
 
-		for _, svc := range ctr.handlers {
+	for _, sub := range m.subModules {
 
-			// pre → handler → post
-			chain := append(append(m.preMiddlewares, svc.Handler), m.postMiddlewares...)
-			router.Handle(svc.Method, svc.Uri, chain...)
+		if sm, ok := sub.(*SolaModule); ok {
+
+			sm.mount(router.Group(sm.Uri()), pre, post)
+			continue
 		}
+
+		// Other Module implementations mount themselves; only pre-middleware
+		// can be inherited through the group
+		sub.SetRoutes(router.Group(sub.Uri(), pre...))
 	}
 }
 
 // diMiddleware returns a Gin middleware that resolves and injects dependencies for each request.
-// It sets each dependency instance in the context under DependencyPrefix+key.
-func diMiddleware(deps *[]*container.DependencyConfig) gin.HandlerFunc {
+// It opens a per-request container.Scope (unless one is already open), so scoped providers
+// share one instance per request, sets each dependency instance in the request context
+// under container.NewContextKey(key), and disposes the scope once the request completes.
+// A dependency failing to resolve aborts the request with a 500 error, attached with c.Error.
+func diMiddleware(cont *container.Container, deps *[]*container.DependencyConfig) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 
 		ctx := c.Request.Context()
 
+		if container.ScopeFromContext(ctx) == nil {
+
+			var scope *container.Scope
+			ctx, scope = container.NewScope(ctx)
+
+			defer func() {
+
+				if err := scope.Dispose(); err != nil {
+
+					_ = c.Error(err)
+				}
+			}()
+		}
+
 		seen := make(map[string]struct{}, len(*deps))
 		for _, d := range *deps {
 
 			if _, dup := seen[d.Key]; dup {
 
-				panic("duplicate dependency key :: " + d.Key)
+				abortWithError(c, fmt.Errorf("duplicate dependency key :: %s", d.Key))
+				return
 			}
 			seen[d.Key] = struct{}{}
 
@@ -273,15 +539,16 @@ func diMiddleware(deps *[]*container.DependencyConfig) gin.HandlerFunc {
 			var err error
 			if d.Type != nil {
 
-				inst, err = container.ResolveByType(d.Key, d.Type)
+				inst, err = cont.ResolveByTypeContext(ctx, d.Key, d.Type)
 			} else {
 
-				inst, err = container.Resolve(d.Key)
+				inst, err = cont.ResolveContext(ctx, d.Key)
 			}
 
 			if err != nil {
 
-				panic(fmt.Errorf("failed to resolve %q: %w", d.Key, err))
+				abortWithError(c, fmt.Errorf("failed to resolve %q :: %w", d.Key, err))
+				return
 			}
 
 			ctx = context.WithValue(ctx, container.NewContextKey(d.Key), inst)
@@ -323,3 +590,30 @@ func (ctr *SolaController) Handlers() []*SolaService {
 
 	return ctr.handlers
 }
+
+// SetPrefix sets a sub-path mounted between the module URI and each handler's Uri
+// (e.g., "/admin" turns "/users" + "/:id" into "/users/admin/:id").
+func (ctr *SolaController) SetPrefix(prefix string) {
+
+	ctr.prefix = prefix
+}
+
+// Prefix returns the controller's sub-path, or "" if its handlers mount directly on the module.
+func (ctr *SolaController) Prefix() string {
+
+	return ctr.prefix
+}
+
+// SetMiddlewares replaces the controller's middleware chain, which runs after the
+// module's pre-middleware and before each of the controller's handlers.
+func (ctr *SolaController) SetMiddlewares(middlewares ...gin.HandlerFunc) {
+
+	ctr.middlewares = make([]gin.HandlerFunc, 0)
+	ctr.middlewares = append(ctr.middlewares, middlewares...)
+}
+
+// Middlewares returns the controller's middleware chain.
+func (ctr *SolaController) Middlewares() []gin.HandlerFunc {
+
+	return ctr.middlewares
+}
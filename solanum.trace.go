@@ -0,0 +1,236 @@
+package solanum
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderRequestID is the header carrying the ID of a request.
+	HeaderRequestID = "X-Request-ID"
+
+	// HeaderTraceparent is the W3C Trace Context header identifying the caller's span.
+	HeaderTraceparent = "traceparent"
+
+	// HeaderTracestate is the W3C Trace Context header carrying vendor-specific trace data.
+	HeaderTracestate = "tracestate"
+
+	// MiddlewareRequestID sets the request ID and trace context of requests (priority -50)
+	MiddlewareRequestID = "solanum.requestid"
+
+	// maxRequestIDLength bounds the length of request IDs accepted from clients.
+	maxRequestIDLength = 128
+)
+
+type (
+	// requestIDContextKey is the context key the request ID is stored under, apart
+	// from the keys of dependencies; read it with RequestID.
+	requestIDContextKey struct{}
+
+	// spanContextKey is the context key the TraceContext is stored under, apart from
+	// the keys of dependencies; read it with SpanContext.
+	spanContextKey struct{}
+)
+
+// TraceContext identifies the span of a request within a W3C Trace Context trace.
+type TraceContext struct {
+	// TraceID the 32 hex digit ID of the whole trace
+	TraceID string
+
+	// SpanID the 16 hex digit ID of the span serving the request
+	SpanID string
+
+	// ParentSpanID the 16 hex digit ID of the caller's span; empty if the request started the trace
+	ParentSpanID string
+
+	// Flags the trace flags; bit 0 is the sampled flag
+	Flags byte
+
+	// TraceState the tracestate header received with the request, passed on as is
+	TraceState string
+}
+
+// IsValid reports whether the trace context has a trace and span ID.
+func (tc TraceContext) IsValid() bool {
+
+	return tc.TraceID != "" && tc.SpanID != ""
+}
+
+// Sampled reports whether the caller recorded the trace.
+func (tc TraceContext) Sampled() bool {
+
+	return tc.Flags&0x01 != 0
+}
+
+// Traceparent renders the traceparent header identifying the span, which outbound
+// requests send to make their spans children of it.
+func (tc TraceContext) Traceparent() string {
+
+	return fmt.Sprintf("00-%s-%s-%02x", tc.TraceID, tc.SpanID, tc.Flags)
+}
+
+// RequestID returns the ID of the request ctx belongs to, or an empty string.
+func RequestID(ctx context.Context) string {
+
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// SpanContext returns the trace context of the request ctx belongs to.
+// It is not valid if ctx does not belong to a request.
+func SpanContext(ctx context.Context) TraceContext {
+
+	tc, _ := ctx.Value(spanContextKey{}).(TraceContext)
+	return tc
+}
+
+// PropagateHeaders sets the request ID and trace context of ctx on the headers of an
+// outbound request, so that the services it calls join the same request and trace.
+func PropagateHeaders(ctx context.Context, h http.Header) {
+
+	if id := RequestID(ctx); id != "" {
+
+		h.Set(HeaderRequestID, id)
+	}
+
+	if tc := SpanContext(ctx); tc.IsValid() {
+
+		h.Set(HeaderTraceparent, tc.Traceparent())
+		if tc.TraceState != "" {
+
+			h.Set(HeaderTracestate, tc.TraceState)
+		}
+	}
+}
+
+// RequestTracing returns a middleware identifying every request. It keeps the
+// X-Request-ID sent by the client, if well-formed, or generates one. It continues
+// the trace of a valid traceparent header, or starts a new one, with a new span ID
+// for the request. Both are stored in the request context, readable through RequestID
+// and SpanContext, and echoed in the X-Request-ID, traceparent and tracestate
+// response headers.
+func RequestTracing() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID(id) {
+
+			id = randomHex(16)
+		}
+
+		tc, ok := parseTraceparent(c.GetHeader(HeaderTraceparent))
+		if ok {
+
+			tc.TraceState = c.GetHeader(HeaderTracestate)
+		} else {
+
+			tc = TraceContext{TraceID: randomHex(16)}
+		}
+		tc.SpanID = randomHex(8)
+
+		ctx := context.WithValue(c.Request.Context(), requestIDContextKey{}, id)
+		ctx = context.WithValue(ctx, spanContextKey{}, tc)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Header(HeaderRequestID, id)
+		c.Header(HeaderTraceparent, tc.Traceparent())
+		if tc.TraceState != "" {
+
+			c.Header(HeaderTracestate, tc.TraceState)
+		}
+
+		c.Next()
+	}
+}
+
+// parseTraceparent parses a traceparent header into the trace context of the caller,
+// with the caller's span as parent. Versions above 00 are parsed as version 00,
+// ignoring any trailing fields, as the W3C specification requires.
+func parseTraceparent(header string) (TraceContext, bool) {
+
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	if len(header) < 55 || (len(header) > 55 && header[55] != '-') {
+
+		return TraceContext{}, false
+	}
+
+	parts := strings.Split(header[:55], "-")
+	if len(parts) != 4 || !isLowerHex(parts[0], 2) || parts[0] == "ff" ||
+		!isLowerHex(parts[1], 32) || !isLowerHex(parts[2], 16) || !isLowerHex(parts[3], 2) {
+
+		return TraceContext{}, false
+	}
+
+	if parts[0] == "00" && len(header) != 55 {
+
+		return TraceContext{}, false
+	}
+
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+
+		return TraceContext{}, false
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+
+		return TraceContext{}, false
+	}
+
+	return TraceContext{TraceID: parts[1], ParentSpanID: parts[2], Flags: flags[0]}, true
+}
+
+// isLowerHex reports whether s is made of n lower-case hex digits.
+func isLowerHex(s string, n int) bool {
+
+	if len(s) != n {
+
+		return false
+	}
+
+	for _, r := range s {
+
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// validRequestID reports whether a client-provided request ID is safe to keep:
+// non-empty, bounded in length, and made of printable ASCII without spaces.
+func validRequestID(id string) bool {
+
+	if id == "" || len(id) > maxRequestIDLength {
+
+		return false
+	}
+
+	for _, r := range id {
+
+		if r <= ' ' || r > '~' {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// randomHex returns n random bytes, hex encoded.
+func randomHex(n int) string {
+
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,174 @@
+package solanum_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// closeRecorder records the order in which instances are closed.
+type closeRecorder struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+// Close implements io.Closer.
+func (r *closeRecorder) Close() error {
+	*r.closed = append(*r.closed, r.name)
+	return r.err
+}
+
+// TestShutdownClosesInReverseDependencyOrder verifies dependents are closed before their dependencies.
+func TestShutdownClosesInReverseDependencyOrder(t *testing.T) {
+	var closed []string
+	c := container.New()
+
+	type db struct{ *closeRecorder }
+	type repo struct{ *closeRecorder }
+
+	c.Register("db", func() *db { return &db{&closeRecorder{name: "db", closed: &closed}} })
+	c.Register("repo", func(d *db) *repo { return &repo{&closeRecorder{name: "repo", closed: &closed}} })
+	c.Register("cache", "not a closer")
+	c.Register("pool", func() []string { return []string{"conn"} },
+		container.WithClose(func(inst any) error {
+			closed = append(closed, "pool")
+			return nil
+		}),
+	)
+
+	_, err := c.Resolve("pool")
+	assert.NoError(t, err)
+	_, err = c.Resolve("repo")
+	assert.NoError(t, err)
+	_, err = c.Resolve("cache")
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.Equal(t, []string{"repo", "db", "pool"}, closed)
+
+	// A second shutdown has nothing left to close
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.Len(t, closed, 3)
+}
+
+// TestRunnerShutdownJoinsCloseErrors verifies the runner closes module and runner containers
+// and reports every close error.
+func TestRunnerShutdownJoinsCloseErrors(t *testing.T) {
+	var closed []string
+	errApp := errors.New("app close failed")
+	errBilling := errors.New("billing close failed")
+
+	app := container.New()
+	app.Register("app", &closeRecorder{name: "app", closed: &closed, err: errApp})
+
+	billing := app.NewChild()
+	billing.Register("billing", &closeRecorder{name: "billing", closed: &closed, err: errBilling})
+
+	m := solanum.NewModule(
+		solanum.WithUri("/billing"),
+		solanum.WithModuleContainer(billing),
+		solanum.WithDependency(container.DepConfig[*closeRecorder]("billing")),
+		solanum.WithDependency(container.DepConfig[*closeRecorder]("app")),
+	)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(0), solanum.WithContainer(app))
+	runner.SetModules(m)
+
+	err := runner.Shutdown(context.Background())
+	assert.ErrorIs(t, err, errApp)
+	assert.ErrorIs(t, err, errBilling)
+	assert.Equal(t, []string{"billing", "app"}, closed)
+}
+
+// racingResource is built by several goroutines at once, and counts its closes.
+type racingResource struct{ closed atomic.Int32 }
+
+// Close implements io.Closer.
+func (r *racingResource) Close() error {
+	r.closed.Add(1)
+	return nil
+}
+
+// racingFactory returns a factory that only returns once n calls are under way,
+// so that n concurrent resolves all construct an instance, and the instances built.
+func racingFactory(n int) (func() *racingResource, *[]*racingResource) {
+	var mu sync.Mutex
+	var built []*racingResource
+	var entered sync.WaitGroup
+	entered.Add(n)
+
+	return func() *racingResource {
+		r := &racingResource{}
+		mu.Lock()
+		built = append(built, r)
+		mu.Unlock()
+
+		entered.Done()
+		entered.Wait()
+		return r
+	}, &built
+}
+
+// resolveConcurrently resolves key n times at once and returns the instances.
+func resolveConcurrently(t *testing.T, n int, resolve func() (any, error)) []any {
+	insts := make([]any, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			inst, err := resolve()
+			assert.NoError(t, err)
+			insts[i] = inst
+		}(i)
+	}
+	wg.Wait()
+
+	return insts
+}
+
+// TestConcurrentResolveReleasesLosingInstances verifies instances built by resolves
+// that lost the race to be cached are closed, while the kept one lives on until
+// shutdown or disposal.
+func TestConcurrentResolveReleasesLosingInstances(t *testing.T) {
+	const n = 4
+	c := container.New()
+
+	singleton, builtSingletons := racingFactory(n)
+	c.Register("singleton", singleton)
+	scoped, builtScoped := racingFactory(n)
+	c.Register("scoped", scoped, container.WithScoped())
+
+	ctx, scope := container.NewScope(context.Background())
+	for key, built := range map[string]*[]*racingResource{"singleton": builtSingletons, "scoped": builtScoped} {
+		insts := resolveConcurrently(t, n, func() (any, error) { return c.ResolveContext(ctx, key) })
+		kept := insts[0].(*racingResource)
+		for _, inst := range insts {
+			assert.Same(t, kept, inst, key)
+		}
+
+		assert.Len(t, *built, n, key)
+		for _, r := range *built {
+			if r == kept {
+				assert.Zero(t, r.closed.Load(), key)
+			} else {
+				assert.Equal(t, int32(1), r.closed.Load(), key)
+			}
+		}
+	}
+
+	assert.NoError(t, scope.Dispose())
+	assert.NoError(t, c.Shutdown(context.Background()))
+	for _, r := range append(*builtSingletons, *builtScoped...) {
+		assert.Equal(t, int32(1), r.closed.Load())
+	}
+}
@@ -1,6 +1,7 @@
 package solanum_test
 
 import (
+	"context"
 	solanum "github.com/annuums/solanum/container"
 	"reflect"
 	"testing"
@@ -68,3 +69,41 @@ func TestResolveByTypeMismatch(t *testing.T) {
 	_, err := solanum.ResolveByType("bad", reflect.TypeOf((*MyIfc)(nil)).Elem())
 	assert.Error(t, err)
 }
+
+// scopedResource counts how often it was closed, to observe scope disposal.
+type scopedResource struct{ closed int }
+
+// Close implements io.Closer.
+func (r *scopedResource) Close() error {
+	r.closed++
+	return nil
+}
+
+// TestRegisterResolveScoped verifies scoped providers share one instance per scope.
+func TestRegisterResolveScoped(t *testing.T) {
+	solanum.Register("scoped", func() *scopedResource { return &scopedResource{} }, solanum.WithScoped())
+
+	// Outside of a scope, scoped providers cannot be resolved
+	_, err := solanum.Resolve("scoped")
+	assert.Error(t, err)
+
+	ctx, scope := solanum.NewScope(context.Background())
+	first, err := solanum.ResolveContext(ctx, "scoped")
+	assert.NoError(t, err)
+	second, err := solanum.ResolveContext(ctx, "scoped")
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// A different scope gets its own instance
+	otherCtx, otherScope := solanum.NewScope(context.Background())
+	other, err := solanum.ResolveContext(otherCtx, "scoped")
+	assert.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	// Disposing the scope closes its instances exactly once
+	assert.NoError(t, scope.Dispose())
+	assert.NoError(t, scope.Dispose())
+	assert.Equal(t, 1, first.(*scopedResource).closed)
+	assert.Equal(t, 0, other.(*scopedResource).closed)
+	assert.NoError(t, otherScope.Dispose())
+}
@@ -1,7 +1,9 @@
 package solanum_test
 
 import (
+	"context"
 	"github.com/annuums/solanum/container"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -16,7 +18,7 @@ func init() {
 
 // TestDep ensures that Dep constructs the correct DependencyConfig for type T.
 func TestDep(t *testing.T) {
-	dc := container.Dep[int]("intKey")
+	dc := container.DepConfig[int]("intKey")
 	assert.Equal(t, "intKey", dc.Key)
 	assert.Equal(t, reflect.TypeOf(0), dc.Type)
 }
@@ -24,14 +26,15 @@ func TestDep(t *testing.T) {
 // TestGetDependency retrieves an injected value or returns zero value if absent.
 func TestGetDependency(t *testing.T) {
 	// Create test context
-	tc := &gin.Context{}
+	tc := &gin.Context{Request: httptest.NewRequest("GET", "/", nil)}
 
-	// Manually set a dependency in context
-	tc.Set(container.DependencyPrefix+"foo", "bar")
-	val := container.DepFromContext[string](tc, "foo")
+	// Manually set a dependency in the request context
+	ctx := context.WithValue(tc.Request.Context(), container.NewContextKey("foo"), "bar")
+	tc.Request = tc.Request.WithContext(ctx)
+	val := container.DepFromGinContext[string](tc, "foo")
 	assert.Equal(t, "bar", val)
 
 	// Missing key returns zero of type
-	zero := container.DepFromContext[bool](tc, "missing")
+	zero := container.DepFromGinContext[bool](tc, "missing")
 	assert.False(t, zero)
 }
@@ -5,94 +5,43 @@ import (
 	"github.com/annuums/solanum"
 	container2 "github.com/annuums/solanum/container"
 	"github.com/stretchr/testify/assert"
-	"reflect"
-	"sync"
 	"testing"
 )
 
-type providerEntry struct {
-	// factory constructs a new instance of the provider.
-	factory func() interface{}
-
-	// singleton indicates whether to reuse the same instance across resolves.
-	singleton bool
-
-	// instance holds the created singleton instance after first resolution.
-	instance interface{}
-
-	// hookCalled tracks if the initHook has already been executed.
-	hookCalled bool
-
-	// initHook is an optional callback that runs once after creating the instance.
-	initHook func(interface{})
-
-	// interfaceType, if non-nil, registers this provider under a Go interface type.
-	interfaceType reflect.Type
-
-	// providerType is the concrete type returned by the factory or provided directly.
-	providerType reflect.Type
-}
-
-type container struct {
-	mu           sync.RWMutex              // protects all maps below
-	providers    map[string]*providerEntry // key -> providerEntry
-	interfaceMap map[reflect.Type]string   // interface type -> key
-	typeMap      map[reflect.Type]string   // concrete type -> key
-}
-
-// globalContainer is the shared, package-level DI container instance.
-var globalContainer = &container{
-	providers:    make(map[string]*providerEntry),
-	interfaceMap: make(map[reflect.Type]string),
-	typeMap:      make(map[reflect.Type]string),
-}
-
-// resetContainer clears the global DI container state for test isolation.
-func resetContainer() {
-	globalContainer = &container{
-		providers:    make(map[string]*providerEntry),
-		interfaceMap: make(map[reflect.Type]string),
-		typeMap:      make(map[reflect.Type]string),
-	}
-}
-
 // TestValidateDependencies_OK ensures that ValidateDependencies passes when all deps are registered.
 func TestValidateDependencies_OK(t *testing.T) {
-	resetContainer()
+	c := container2.New()
 
 	// Register a dummy provider for key "foo"
-	container2.Register("foo", func() int { return 123 }, container2.WithSingleton())
+	c.Register("foo", func() int { return 123 }, container2.WithSingleton())
 
 	// Create a module that depends on "foo"
-	mod := solanum.NewModule("/test")
-	mod.SetDependencies(*container2.Dep[int]("foo"))
+	mod := solanum.NewModule(solanum.WithUri("/test"))
+	mod.SetDependencies(*container2.DepConfig[int]("foo"))
 
-	// Setup runner with the module
-	r := solanum.NewSolanum(0)
-	runnerIface := *r // Runner interface
-	runnerConcrete := runnerIface.(solanum.Runner)
-	runnerConcrete.SetModules(mod)
+	// Setup runner with the module; the module inherits the runner's container
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(0), solanum.WithContainer(c))
+	runner.SetModules(mod)
 
 	// Validate should pass (no error)
-	err := runnerConcrete.ValidateDependencies()
+	err := runner.ValidateDependencies()
 	assert.NoError(t, err)
 }
 
 // TestValidateDependencies_FailureMissing reports an error when a dependency is not registered.
 func TestValidateDependencies_FailureMissing(t *testing.T) {
-	resetContainer()
-
 	// Do not register any provider for "missing"
+	c := container2.New()
 
-	mod := solanum.NewModule("/test")
-	mod.SetDependencies(*container2.Dep[string]("missing"))
+	mod := solanum.NewModule(solanum.WithUri("/test"))
+	mod.SetDependencies(*container2.DepConfig[string]("missing"))
 
-	r := solanum.NewSolanum(0)
-	runnerIface := *r
-	runnerConcrete := runnerIface.(solanum.Runner)
-	runnerConcrete.SetModules(mod)
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(0), solanum.WithContainer(c))
+	runner.SetModules(mod)
 
-	err := runnerConcrete.ValidateDependencies()
+	err := runner.ValidateDependencies()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "dependency validation failed for key=\"missing\"")
 }
@@ -108,10 +57,10 @@ func NewDummyService(db *sql.DB) *DummyService {
 
 // TestWithDepInjection verifies that WithDep option causes correct injection by key.
 func TestWithDepInjection(t *testing.T) {
-	resetContainer()
+	c := container2.New()
 
 	// Register *sql.DB as singleton under key "db"
-	container2.Register(
+	c.Register(
 		"db",
 		func() *sql.DB {
 			// Return a dummy *sql.DB (nil is ok for test)
@@ -121,7 +70,7 @@ func TestWithDepInjection(t *testing.T) {
 	)
 
 	// Register DummyService with transient scope and WithDep for "db"
-	container2.Register(
+	c.Register(
 		"svc",
 		func(ds *sql.DB) *DummyService {
 			return NewDummyService(ds)
@@ -131,7 +80,7 @@ func TestWithDepInjection(t *testing.T) {
 	)
 
 	// Resolve the service
-	inst, err := container2.Resolve("svc")
+	inst, err := c.Resolve("svc")
 	assert.NoError(t, err)
 
 	svc, ok := inst.(*DummyService)
@@ -141,17 +90,17 @@ func TestWithDepInjection(t *testing.T) {
 
 // TestAutomaticTypeInjection verifies automatic injection when WithDep is not used.
 func TestAutomaticTypeInjection(t *testing.T) {
-	resetContainer()
+	c := container2.New()
 
 	// Register *sql.DB under key "db"
-	container2.Register(
+	c.Register(
 		"db",
 		func() *sql.DB { return &sql.DB{} },
 		container2.WithSingleton(),
 	)
 
 	// Register service without WithDep, relying on reflect-based auto deps
-	container2.Register(
+	c.Register(
 		"svc2",
 		func(ds *sql.DB) *DummyService {
 			return NewDummyService(ds)
@@ -159,10 +108,122 @@ func TestAutomaticTypeInjection(t *testing.T) {
 		container2.WithTransient(),
 	)
 
-	inst, err := container2.Resolve("svc2")
+	inst, err := c.Resolve("svc2")
 	assert.NoError(t, err)
 
 	svc, ok := inst.(*DummyService)
 	assert.True(t, ok)
 	assert.NotNil(t, svc.db)
 }
+
+// TestContainersAreIsolated verifies that separate containers do not share registrations.
+func TestContainersAreIsolated(t *testing.T) {
+	first := container2.New()
+	second := container2.New()
+
+	first.Register("name", "first")
+	second.Register("name", "second")
+
+	inst, err := first.Resolve("name")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", inst)
+
+	inst, err = second.Resolve("name")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", inst)
+
+	_, err = container2.New().Resolve("name")
+	assert.Error(t, err)
+}
+
+// TestChildContainerFallback verifies children resolve locally first, then from their parent.
+func TestChildContainerFallback(t *testing.T) {
+	parent := container2.New()
+	parent.Register("httpClient", "default client")
+	parent.Register("db", func() *sql.DB { return &sql.DB{} })
+
+	child := parent.NewChild()
+	child.Register("httpClient", "billing client")
+	assert.Same(t, parent, child.Parent())
+
+	// Overridden key resolves locally, without touching the parent
+	inst, err := child.Resolve("httpClient")
+	assert.NoError(t, err)
+	assert.Equal(t, "billing client", inst)
+
+	inst, err = parent.Resolve("httpClient")
+	assert.NoError(t, err)
+	assert.Equal(t, "default client", inst)
+
+	// Missing keys fall back to the parent and share its singletons
+	fromChild, err := child.Resolve("db")
+	assert.NoError(t, err)
+	fromParent, err := parent.Resolve("db")
+	assert.NoError(t, err)
+	assert.Same(t, fromParent, fromChild)
+
+	// Type-inferred dependencies fall back as well
+	child.Register("svc", func(db *sql.DB) *DummyService { return NewDummyService(db) })
+	svc, err := child.Resolve("svc")
+	assert.NoError(t, err)
+	assert.Same(t, fromParent, svc.(*DummyService).db)
+}
+
+// userSvc and userRepo depend on each other to exercise cycle detection.
+type userSvc struct{ repo *userRepo }
+type userRepo struct{ svc *userSvc }
+
+// registerUserCycle registers userSvc -> userRepo -> userSvc in c.
+func registerUserCycle(c *container2.Container) {
+	c.Register("userSvc", func(r *userRepo) *userSvc { return &userSvc{repo: r} },
+		container2.WithDep[*userRepo]("userRepo"))
+	c.Register("userRepo", func(s *userSvc) *userRepo { return &userRepo{svc: s} },
+		container2.WithDep[*userSvc]("userSvc"))
+}
+
+// TestResolveDetectsCycle verifies that a dependency cycle is reported as an error with the full chain.
+func TestResolveDetectsCycle(t *testing.T) {
+	c := container2.New()
+	registerUserCycle(c)
+
+	_, err := c.Resolve("userSvc")
+	assert.Error(t, err)
+
+	var cycle *container2.CycleError
+	if assert.ErrorAs(t, err, &cycle) {
+		assert.Equal(t, []string{"userSvc", "userRepo", "userSvc"}, cycle.Chain)
+	}
+	assert.Contains(t, err.Error(), "userSvc -> userRepo -> userSvc")
+
+	// Type-inferred dependencies are followed as well
+	inferred := container2.New()
+	inferred.Register("svc", func(r *userRepo) *userSvc { return &userSvc{repo: r} })
+	inferred.Register("repo", func(s *userSvc) *userRepo { return &userRepo{svc: s} })
+	assert.ErrorContains(t, inferred.CheckCycles(), "repo -> svc -> repo")
+}
+
+// TestValidateDependencies_Cycle reports a cycle during validation instead of overflowing the stack.
+func TestValidateDependencies_Cycle(t *testing.T) {
+	c := container2.New()
+	registerUserCycle(c)
+
+	mod := solanum.NewModule(solanum.WithUri("/users"))
+	mod.SetDependencies(*container2.DepConfig[*userSvc]("userSvc"))
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(0), solanum.WithContainer(c))
+	runner.SetModules(mod)
+
+	err := runner.ValidateDependencies()
+	assert.ErrorContains(t, err, "userRepo -> userSvc -> userRepo")
+}
+
+// TestResolveReturnsFactoryError verifies a failing factory surfaces as an error, not a panic.
+func TestResolveReturnsFactoryError(t *testing.T) {
+	c := container2.New()
+	c.Register("broken", func() (*sql.DB, error) { return nil, sql.ErrConnDone })
+	c.Register("svc", func(db *sql.DB) *DummyService { return NewDummyService(db) })
+
+	_, err := c.Resolve("svc")
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
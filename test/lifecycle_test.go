@@ -0,0 +1,191 @@
+package solanum_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// recordHooks returns start/stop hook options that append "start:name"/"stop:name" to events.
+func recordHooks(name string, events *[]string) []container.RegisterOption {
+	return []container.RegisterOption{
+		container.WithOnStart(func(ctx context.Context, inst any) error {
+			*events = append(*events, "start:"+name)
+			return nil
+		}),
+		container.WithOnStop(func(ctx context.Context, inst any) error {
+			*events = append(*events, "stop:"+name)
+			return nil
+		}),
+	}
+}
+
+// TestContainerStartStopOrder verifies start hooks follow dependency order and stop hooks reverse it.
+func TestContainerStartStopOrder(t *testing.T) {
+	var events []string
+	c := container.New()
+
+	type cache struct{}
+	type consumer struct{}
+	type broker struct{}
+
+	// Registered in an order unrelated to the dependency order: cache -> consumer -> broker
+	c.Register("cache", func(*consumer) *cache { return &cache{} }, recordHooks("cache", &events)...)
+	c.Register("consumer", func(*broker) *consumer { return &consumer{} }, recordHooks("consumer", &events)...)
+	c.Register("broker", func() *broker { return &broker{} }, recordHooks("broker", &events)...)
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start:broker", "start:consumer", "start:cache"}, events)
+
+	events = nil
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"stop:cache", "stop:consumer", "stop:broker"}, events)
+}
+
+// TestContainerStartRollsBackOnFailure verifies a failing start hook stops what already started.
+func TestContainerStartRollsBackOnFailure(t *testing.T) {
+	var events []string
+	errWarmup := errors.New("warmup failed")
+	c := container.New()
+
+	c.Register("db", "db", recordHooks("db", &events)...)
+	c.Register("warmup", func(db string) int { return 1 },
+		container.WithDep[string]("db"),
+		container.WithOnStart(func(ctx context.Context, inst any) error { return errWarmup }),
+	)
+
+	err := c.Start(context.Background())
+	assert.ErrorIs(t, err, errWarmup)
+	assert.Equal(t, []string{"start:db", "stop:db"}, events)
+}
+
+// TestRunnerStartAbortsOnModuleHookFailure verifies module hooks run after provider hooks
+// and that a failing module hook aborts startup and rolls back.
+func TestRunnerStartAbortsOnModuleHookFailure(t *testing.T) {
+	var events []string
+	errModule := errors.New("module failed")
+
+	c := container.New()
+	c.Register("consumer", "consumer", recordHooks("consumer", &events)...)
+
+	ok := solanum.NewModule(
+		solanum.WithUri("/ok"),
+		solanum.WithOnStart(func(ctx context.Context) error {
+			events = append(events, "start:ok")
+			return nil
+		}),
+		solanum.WithOnStop(func(ctx context.Context) error {
+			events = append(events, "stop:ok")
+			return nil
+		}),
+	)
+	failing := solanum.NewModule(
+		solanum.WithUri("/failing"),
+		solanum.WithOnStart(func(ctx context.Context) error { return errModule }),
+	)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(freePort(t)), solanum.WithContainer(c))
+	runner.SetModules(ok, failing)
+
+	err := runner.Start(context.Background())
+	assert.ErrorIs(t, err, errModule)
+	assert.Equal(t, []string{"start:consumer", "start:ok", "stop:ok", "stop:consumer"}, events)
+}
+
+// TestRunnerLifecycleHooks verifies hooks run around serving, in start and reverse order.
+func TestRunnerLifecycleHooks(t *testing.T) {
+	var events []string
+
+	c := container.New()
+	c.Register("consumer", "consumer", recordHooks("consumer", &events)...)
+
+	m := solanum.NewModule(
+		solanum.WithUri("/hooks"),
+		solanum.WithOnStart(func(ctx context.Context) error {
+			events = append(events, "start:module")
+			return nil
+		}),
+		solanum.WithOnStop(func(ctx context.Context) error {
+			events = append(events, "stop:module")
+			return nil
+		}),
+	)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(freePort(t)), solanum.WithContainer(c))
+	runner.SetModules(m)
+
+	require.NoError(t, runner.Start(context.Background()))
+	assert.Equal(t, []string{"start:consumer", "start:module"}, events)
+
+	require.NoError(t, runner.Shutdown(context.Background()))
+	assert.Equal(t, []string{"start:consumer", "start:module", "stop:module", "stop:consumer"}, events)
+}
+
+// TestShutdownStopTimeout verifies stop hooks get a context bounded by the stop timeout,
+// even when Shutdown is called with one that never expires, as Run does.
+func TestShutdownStopTimeout(t *testing.T) {
+	m := solanum.NewModule(
+		solanum.WithUri("/hooks"),
+		solanum.WithOnStop(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+
+	runner := solanum.New(solanum.WithStopTimeout(50 * time.Millisecond))
+	runner.SetModules(m)
+	require.NoError(t, runner.Start(context.Background()))
+
+	start := time.Now()
+	err := runner.Shutdown(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestStartHooksCanUseRunner verifies start hooks can call back into the runner
+// without deadlocking Start, and that Start cannot run twice at once.
+func TestStartHooksCanUseRunner(t *testing.T) {
+	var runner solanum.Runner
+	var addrs []any
+
+	c := container.New()
+	c.Register("consumer", "consumer", container.WithOnStart(func(ctx context.Context, inst any) error {
+		addrs = append(addrs, runner.Addr())
+		return nil
+	}))
+
+	m := solanum.NewModule(
+		solanum.WithUri("/hooks"),
+		solanum.WithOnStart(func(ctx context.Context) error {
+			addrs = append(addrs, runner.Addr())
+			assert.EqualError(t, runner.Start(ctx), "server is already running")
+			return nil
+		}),
+	)
+
+	runner = solanum.New(solanum.WithContainer(c))
+	runner.SetModules(m)
+
+	started := make(chan error, 1)
+	go func() { started <- runner.Start(context.Background()) }()
+
+	select {
+	case err := <-started:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start deadlocked")
+	}
+	defer runner.Shutdown(context.Background())
+
+	assert.Equal(t, []any{nil, nil}, addrs, "the server is not bound while hooks run")
+	assert.NotNil(t, runner.Addr())
+}
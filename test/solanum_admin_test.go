@@ -0,0 +1,135 @@
+package solanum_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+	"github.com/annuums/solanum/util"
+)
+
+// adminRepo is a provider bound to an interface, for the admin container view.
+type adminRepo struct{ dsn string }
+
+// FindAll implements adminFinder.
+func (r *adminRepo) FindAll() []string { return nil }
+
+// adminFinder is the interface adminRepo is bound to.
+type adminFinder interface{ FindAll() []string }
+
+// TestAdminModule verifies the admin module requires authentication and reports
+// routes with their handlers, container providers with their edges, and CORS.
+func TestAdminModule(t *testing.T) {
+
+	c := container.New()
+	c.Register("dsn", "postgres://db")
+	c.Register("repo", func(dsn string) (*adminRepo, error) { return &adminRepo{dsn: dsn}, nil },
+		container.WithDep[string]("dsn"), container.As((*adminFinder)(nil)))
+	c.Register("session", func() (string, error) { return "s", nil }, container.WithScoped())
+
+	moduleContainer := c.NewChild()
+	moduleContainer.Register("audit", func(r *adminRepo) (int, error) { return 1, nil })
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "/:id", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		c.Status(http.StatusOK)
+	}})
+	m := solanum.NewModule(solanum.WithUri("/orders"), solanum.WithModuleContainer(moduleContainer))
+	m.SetControllers(ctr)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(5057),
+		solanum.WithContainer(c),
+		solanum.WithAdmin("/admin", solanum.BearerToken("s3cret")),
+	)
+	runner.Cors(util.WithUrls([]string{"https://app.example.com"}), util.WithAllowCredentials(true))
+	runner.SetModules(m)
+	runner.InitGlobalMiddlewares()
+	runner.InitModules()
+
+	_, err := c.Resolve("repo")
+	require.NoError(t, err)
+
+	for _, auth := range []string{"", "Bearer wrong", "s3cret"} {
+
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		runner.GinEngine().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, auth)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report solanum.AdminReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+
+	var orders *solanum.AdminRoute
+	for i := range report.Routes {
+
+		if report.Routes[i].Path == "/orders/:id" {
+
+			orders = &report.Routes[i]
+		}
+	}
+	require.NotNil(t, orders)
+	assert.Equal(t, "/orders", orders.Module)
+	assert.Equal(t, http.MethodGet, orders.Method)
+	assert.Equal(t, []string{solanum.MiddlewareRequestID, solanum.MiddlewareErrors, solanum.MiddlewareRecovery, solanum.MiddlewareCors}, orders.Handlers[:4])
+	assert.Equal(t, orders.Handlers[:4], report.Middlewares)
+
+	require.Len(t, report.Containers, 2)
+	assert.Equal(t, "runner", report.Containers[0].Name)
+	assert.Equal(t, []container.ProviderInfo{
+		{Key: "dsn", Lifetime: "singleton", Type: "string", Instantiated: true},
+		{Key: solanum.LoggerKey, Lifetime: "singleton", Type: "*slog.Logger"},
+		{
+			Key:          "repo",
+			Lifetime:     "singleton",
+			Type:         "*solanum_test.adminRepo",
+			Interface:    "solanum_test.adminFinder",
+			Instantiated: true,
+			Dependencies: []container.DependencyInfo{{Key: "dsn", Type: "string"}},
+		},
+		{Key: "session", Lifetime: "scoped", Type: "string"},
+	}, report.Containers[0].Providers)
+
+	assert.Equal(t, "/orders", report.Containers[1].Name)
+	assert.Equal(t, "runner", report.Containers[1].Parent)
+	assert.Equal(t, []container.ProviderInfo{{
+		Key:          "audit",
+		Lifetime:     "singleton",
+		Type:         "int",
+		Dependencies: []container.DependencyInfo{{Key: "repo", Type: "*solanum_test.adminRepo"}},
+	}}, report.Containers[1].Providers)
+
+	require.NotNil(t, report.Cors)
+	assert.Equal(t, []string{"https://app.example.com"}, report.Cors.AllowOrigins)
+	assert.True(t, report.Cors.AllowCredentials)
+}
+
+// TestAdminModuleNeedsAuth verifies the admin module is not served without auth.
+func TestAdminModuleNeedsAuth(t *testing.T) {
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(5058), solanum.WithAdmin("/admin", nil))
+	runner.InitModules()
+
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -1,25 +1,31 @@
 package solanum_test
 
 import (
+	"context"
+	"fmt"
 	"github.com/annuums/solanum/util"
+	"io"
+	"net/http"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
 )
 
 // TestNewSolanumSingleton ensures NewSolanum returns the same Runner instance.
 func TestNewSolanumSingleton(t *testing.T) {
-	first := solanum.NewSolanum(1234)
-	second := solanum.NewSolanum(5678)
+	first := solanum.NewSolanum(solanum.WithPort(1234))
+	second := solanum.NewSolanum(solanum.WithPort(5678))
 	assert.Equal(t, first, second)
 }
 
 // TestGinEngineAccess verifies that GinEngine returns a non-nil *gin.Engine.
 func TestGinEngineAccess(t *testing.T) {
-	app := *solanum.NewSolanum(5050)
+	app := solanum.NewSolanum(solanum.WithPort(5050))
 	eng := app.GinEngine()
 	assert.NotNil(t, eng)
 }
@@ -28,7 +34,7 @@ func TestGinEngineAccess(t *testing.T) {
 func TestCorsIntegration(t *testing.T) {
 	// Set Gin mode to test to avoid logs
 	gin.SetMode(gin.TestMode)
-	app := *solanum.NewSolanum(5050)
+	app := solanum.NewSolanum(solanum.WithPort(5050))
 	// Should not panic
 	assert.NotPanics(t, func() {
 		app.Cors(
@@ -37,3 +43,62 @@ func TestCorsIntegration(t *testing.T) {
 		)
 	})
 }
+
+// TestNewIndependentRunners verifies runners built with New have their own engine,
+// modules and container, serve different ports side by side, and leave the global runner alone.
+func TestNewIndependentRunners(t *testing.T) {
+	t.Parallel()
+
+	global := solanum.SolanumRunner
+
+	newRunner := func(greeting string) solanum.Runner {
+		runner := solanum.New(solanum.WithPort(0))
+		runner.Container().Register("greeting", greeting)
+
+		m := solanum.NewModule(solanum.WithUri("/hello"))
+		m.SetDependencies(*container.DepConfig[string]("greeting"))
+		ctr := solanum.NewController()
+		ctr.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: func(c *gin.Context) {
+			c.String(http.StatusOK, container.DepFromGinContext[string](c, "greeting"))
+		}})
+		m.SetControllers(ctr)
+		runner.SetModules(m)
+
+		require.NoError(t, runner.Start(context.Background()))
+		t.Cleanup(func() { assert.NoError(t, runner.Shutdown(context.Background())) })
+
+		return runner
+	}
+
+	public, internal := newRunner("public"), newRunner("internal")
+	assert.NotSame(t, public.GinEngine(), internal.GinEngine())
+	assert.NotSame(t, public.Container(), internal.Container())
+	assert.NotSame(t, container.Default(), public.Container())
+	assert.Same(t, global, solanum.SolanumRunner)
+
+	for runner, want := range map[solanum.Runner]string{public: "public", internal: "internal"} {
+		resp, err := http.Get(fmt.Sprintf("http://%s/hello", runner.Addr()))
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, want, string(body))
+	}
+}
+
+// TestWithContainerRebindsInheritingModules verifies modules registered before
+// WithContainer follow the runner to its new container.
+func TestWithContainerRebindsInheritingModules(t *testing.T) {
+	own := container.New()
+	c := container.New()
+
+	inheriting := solanum.NewModule(solanum.WithUri("/a"))
+	isolated := solanum.NewModule(solanum.WithUri("/b"), solanum.WithModuleContainer(own))
+
+	runner := solanum.New(solanum.WithPort(0))
+	runner.SetModules(inheriting, isolated)
+	solanum.WithContainer(c)(runner)
+
+	assert.Same(t, c, runner.Container())
+	assert.Same(t, c, inheriting.Container())
+	assert.Same(t, own, isolated.Container())
+}
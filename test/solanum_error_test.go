@@ -0,0 +1,136 @@
+package solanum_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// errOutOfStock is a sentinel error mapped to 409 Conflict.
+var errOutOfStock = errors.New("item is out of stock")
+
+// quotaError is an error type mapped to 429 Too Many Requests.
+type quotaError struct{ limit int }
+
+// Error implements the error interface.
+func (e *quotaError) Error() string { return fmt.Sprintf("quota of %d exceeded", e.limit) }
+
+func init() {
+
+	solanum.RegisterError(errOutOfStock, http.StatusConflict, "out_of_stock")
+	solanum.RegisterErrorType[*quotaError](http.StatusTooManyRequests, "quota_exceeded")
+}
+
+// serveError serves a request whose handler attaches err, and decodes the problem.
+func serveError(t *testing.T, debug bool, err error) (*httptest.ResponseRecorder, solanum.Problem) {
+
+	r := gin.New()
+	r.Use(solanum.ErrorHandler(debug))
+	r.GET("/orders", func(c *gin.Context) { _ = c.Error(err) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	var p solanum.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	return rec, p
+}
+
+// TestErrorHandlerRendersProblems verifies errors are rendered as problem+json.
+func TestErrorHandlerRendersProblems(t *testing.T) {
+
+	rec, p := serveError(t, false, &solanum.Error{
+		Status: http.StatusUnprocessableEntity,
+		Code:   "invalid_order",
+		Detail: "an order needs at least one item",
+		Fields: map[string]string{"items": "must not be empty"},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, solanum.MIMEProblemJSON, rec.Header().Get("Content-Type"))
+	assert.Equal(t, solanum.Problem{
+		Type:     "about:blank",
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "an order needs at least one item",
+		Instance: "/orders",
+		Code:     "invalid_order",
+		Fields:   map[string]string{"items": "must not be empty"},
+	}, p)
+
+	// Registered sentinels match through wrapping
+	rec, p = serveError(t, false, fmt.Errorf("reserve item 3 :: %w", errOutOfStock))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "out_of_stock", p.Code)
+	assert.Equal(t, "item is out of stock", p.Detail)
+
+	rec, p = serveError(t, false, fmt.Errorf("charge :: %w", &quotaError{limit: 10}))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "quota_exceeded", p.Code)
+	assert.Empty(t, p.Detail)
+}
+
+// TestErrorHandlerHidesInternals verifies unknown errors only show details in debug mode.
+func TestErrorHandlerHidesInternals(t *testing.T) {
+
+	err := errors.New("dial tcp 10.0.0.1:5432: connection refused")
+
+	rec, p := serveError(t, false, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "Internal Server Error", p.Title)
+	assert.Empty(t, p.Detail)
+	assert.NotContains(t, rec.Body.String(), "10.0.0.1")
+
+	_, p = serveError(t, true, err)
+	assert.Equal(t, err.Error(), p.Detail)
+
+	// Mapped client errors only show the registered sentinel, not what wraps it
+	wrapped := fmt.Errorf("select stock where secret_col=%d :: %w", 42, errOutOfStock)
+
+	rec, p = serveError(t, false, wrapped)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "item is out of stock", p.Detail)
+	assert.NotContains(t, rec.Body.String(), "secret_col")
+
+	_, p = serveError(t, true, wrapped)
+	assert.Equal(t, "item is out of stock :: "+wrapped.Error(), p.Detail)
+
+	_, p = serveError(t, true, &solanum.Error{Status: http.StatusBadGateway, Detail: "payment provider failed", Err: err})
+	assert.Equal(t, "payment provider failed :: "+err.Error(), p.Detail)
+}
+
+// TestUnresolvableDependencyRendersProblem verifies the DI middleware reports
+// resolution failures instead of panicking.
+func TestUnresolvableDependencyRendersProblem(t *testing.T) {
+
+	c := container.New()
+	c.Register("db", func() (string, error) { return "", errors.New("database is down") })
+
+	m := solanum.NewModule(solanum.WithUri("/orders"), solanum.WithModuleContainer(c))
+	m.SetDependencies(*container.DepConfig[string]("db"))
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		c.String(http.StatusOK, "unreachable")
+	}})
+	m.SetControllers(ctr)
+
+	r := gin.New()
+	r.Use(solanum.ErrorHandler(false))
+	m.SetRoutes(r.Group(m.Uri()))
+
+	rec := httptest.NewRecorder()
+	assert.NotPanics(t, func() { r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil)) })
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, solanum.MIMEProblemJSON, rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), "database is down")
+}
@@ -0,0 +1,185 @@
+package solanum_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// updateItem binds from every source a typed handler supports.
+type updateItem struct {
+	ID     int    `uri:"id" binding:"required"`
+	DryRun bool   `form:"dry_run"`
+	Tenant string `header:"X-Tenant" binding:"required"`
+	Name   string `json:"name" binding:"required,min=2"`
+}
+
+// item is the response of a typed handler.
+type item struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Tenant string `json:"tenant"`
+	DryRun bool   `json:"dryRun"`
+	Owner  string `json:"owner"`
+}
+
+// deleted responds with 204 No Content.
+type deleted struct{}
+
+// StatusCode implements solanum.StatusCoder.
+func (deleted) StatusCode() int { return http.StatusNoContent }
+
+// typedRouter mounts services on a module with an injected "owner" dependency.
+func typedRouter(services ...*solanum.SolaService) *gin.Engine {
+
+	c := container.New()
+	c.Register("owner", "alice")
+
+	m := solanum.NewModule(solanum.WithUri("/items"), solanum.WithModuleContainer(c))
+	m.SetDependencies(*container.DepConfig[string]("owner"))
+	ctr := solanum.NewController()
+	ctr.SetHandlers(services...)
+	m.SetControllers(ctr)
+
+	r := gin.New()
+	r.Use(solanum.ErrorHandler(false))
+	m.SetRoutes(r.Group(m.Uri()))
+	return r
+}
+
+// TestHandleBindsAndEncodes verifies path, query, header and body binding, validation and encoding.
+func TestHandleBindsAndEncodes(t *testing.T) {
+
+	svc := solanum.Handle(http.MethodPut, "/:id", func(ctx context.Context, req updateItem) (item, error) {
+
+		return item{
+			ID:     req.ID,
+			Name:   req.Name,
+			Tenant: req.Tenant,
+			DryRun: req.DryRun,
+			Owner:  container.DepFromContext[string](ctx, "owner"),
+		}, nil
+	})
+	assert.Equal(t, updateItem{}, svc.Request)
+	assert.Equal(t, item{}, svc.Response)
+	assert.Equal(t, http.StatusOK, svc.Meta[solanum.MetaStatus])
+
+	r := typedRouter(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/items/7?dry_run=true", strings.NewReader(`{"name":"lamp"}`))
+	req.Header.Set("X-Tenant", "acme")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":7,"name":"lamp","tenant":"acme","dryRun":true,"owner":"alice"}`, rec.Body.String())
+
+	// Validation failures are reported before the function runs
+	req = httptest.NewRequest(http.MethodPut, "/items/7", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set("X-Tenant", "acme")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, solanum.MIMEProblemJSON, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"Name":"failed on the \"min\" rule"`)
+
+	// Malformed bodies and parameters are rejected too
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPut, "/items/7", strings.NewReader(`{"name":`)),
+		httptest.NewRequest(http.MethodPut, "/items/seven", strings.NewReader(`{"name":"lamp"}`)),
+	} {
+
+		req.Header.Set("X-Tenant", "acme")
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+// TestHandleBindsFormBody verifies form-encoded bodies bind to form tags, alongside
+// the query string, and non-JSON bodies are not decoded as JSON.
+func TestHandleBindsFormBody(t *testing.T) {
+
+	type createNote struct {
+		ID   int    `uri:"id" binding:"required"`
+		Text string `form:"text" binding:"required"`
+		Tag  string `form:"tag"`
+	}
+
+	svc := solanum.Handle(http.MethodPost, "/:id/notes", func(ctx context.Context, req createNote) (createNote, error) {
+
+		return req, nil
+	})
+	r := typedRouter(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/items/7/notes?tag=urgent", strings.NewReader("text=fragile"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"ID":7,"Text":"fragile","Tag":"urgent"}`, rec.Body.String())
+
+	// Required form fields are validated like any other
+	req = httptest.NewRequest(http.MethodPost, "/items/7/notes", strings.NewReader("tag=urgent"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"validation_failed"`)
+
+	// A plain text body is not mistaken for malformed JSON
+	req = httptest.NewRequest(http.MethodPost, "/items/7/notes?text=fragile", strings.NewReader("fragile"))
+	req.Header.Set("Content-Type", "text/plain")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "invalid character")
+}
+
+// TestHandleStatus verifies the default and custom status codes, and failing functions.
+func TestHandleStatus(t *testing.T) {
+
+	create := solanum.Handle(http.MethodPost, "", func(ctx context.Context, req *item) (*item, error) {
+
+		return req, nil
+	})
+	remove := solanum.Handle(http.MethodDelete, "/:id", func(context.Context, struct{}) (deleted, error) {
+
+		return deleted{}, nil
+	})
+	fail := solanum.Handle(http.MethodGet, "/fail", func(context.Context, struct{}) (item, error) {
+
+		return item{}, errors.New("database is down")
+	})
+	assert.Equal(t, http.StatusCreated, create.Meta[solanum.MetaStatus])
+	assert.Equal(t, http.StatusNoContent, remove.Meta[solanum.MetaStatus])
+
+	r := typedRouter(create, remove, fail)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"id":1,"name":"lamp"}`)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"lamp","tenant":"","dryRun":false,"owner":""}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/items/1", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/fail", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "database is down", "internal errors are not exposed")
+}
@@ -0,0 +1,140 @@
+package solanum_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// pingedDB is a provider reporting its health through container.HealthChecker.
+type pingedDB struct{ err error }
+
+// CheckHealth implements container.HealthChecker.
+func (db *pingedDB) CheckHealth(context.Context) error {
+
+	return db.err
+}
+
+// readyz serves /readyz and decodes the report.
+func readyz(t *testing.T, runner solanum.Runner) (int, solanum.HealthReport) {
+
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report solanum.HealthReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+
+	return rec.Code, report
+}
+
+// TestHealthEndpoints verifies liveness always passes and readiness aggregates the
+// checks of providers and of WithReadinessCheck, each bounded by the check timeout.
+func TestHealthEndpoints(t *testing.T) {
+
+	db := &pingedDB{}
+	c := container.New()
+	c.Register("db", func() (*pingedDB, error) { return db, nil })
+	c.Register("cache", "redis://cache", container.WithHealthCheck(func(ctx context.Context, inst any) error {
+
+		assert.Equal(t, "redis://cache", inst)
+		return nil
+	}))
+	c.Register("plain", "no check")
+
+	var slow bool
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(5056),
+		solanum.WithContainer(c),
+		solanum.WithHealth(
+			solanum.WithCheckTimeout(50*time.Millisecond),
+			solanum.WithReadinessCheck("queue", func(ctx context.Context) error {
+
+				if slow {
+
+					<-ctx.Done()
+				}
+				return nil
+			}),
+		),
+	)
+	runner.InitModules()
+
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	code, report := readyz(t, runner)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, solanum.HealthStatusOK, report.Status)
+	assert.ElementsMatch(t, []string{"db", "cache", "queue"}, keysOf(report.Checks))
+
+	db.err = errors.New("connection refused")
+	slow = true
+	code, report = readyz(t, runner)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, solanum.HealthStatusFail, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["db"].Error)
+	assert.Contains(t, report.Checks["queue"].Error, "check timed out after 50ms")
+	assert.Equal(t, solanum.HealthStatusOK, report.Checks["cache"].Status)
+}
+
+// TestReadinessFailsWhileDraining verifies readiness flips to draining as soon as
+// Shutdown starts, while the server keeps serving during the drain delay.
+func TestReadinessFailsWhileDraining(t *testing.T) {
+
+	port := freePort(t)
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(port),
+		solanum.WithContainer(container.New()),
+		solanum.WithHealth(),
+		solanum.WithDrainDelay(300*time.Millisecond),
+	)
+	require.NoError(t, runner.Start(context.Background()))
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/readyz", port)
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	done := make(chan error, 1)
+	go func() { done <- runner.Shutdown(context.Background()) }()
+
+	assert.Eventually(t, func() bool {
+
+		return runner.Readiness(context.Background()).Status == solanum.HealthStatusDraining
+	}, time.Second, 5*time.Millisecond)
+
+	resp, err = http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	require.NoError(t, <-done)
+}
+
+// keysOf returns the keys of a map of check results.
+func keysOf(checks map[string]solanum.CheckResult) []string {
+
+	keys := make([]string, 0, len(checks))
+	for key := range checks {
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
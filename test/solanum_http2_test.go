@@ -0,0 +1,219 @@
+package solanum_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+
+	solanum "github.com/annuums/solanum"
+)
+
+// streamModule returns a module at /stream sending n server-sent events, flushed one by one.
+func streamModule(n int) *solanum.SolaModule {
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		for i := 0; i < n; i++ {
+
+			c.SSEvent("tick", fmt.Sprintf("%d %s", i, c.Request.Proto))
+			c.Writer.Flush()
+		}
+	}})
+
+	m := solanum.NewModule(solanum.WithUri("/stream"))
+	m.SetControllers(ctr)
+
+	return m
+}
+
+// h2cClient returns a client speaking cleartext HTTP/2 with prior knowledge.
+func h2cClient() *http.Client {
+
+	return &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+// TestWithH2C verifies h2c and HTTP/1.1 clients are both served on the same listener,
+// HTTP/1.1 connections can upgrade to h2c, and server-sent events stream over h2c.
+func TestWithH2C(t *testing.T) {
+
+	runner := solanum.New(solanum.WithHost("127.0.0.1"), solanum.WithH2C())
+	runner.SetModules(streamModule(3))
+	runner = startPing(t, runner)
+	base := "http://" + runner.Addr().String()
+
+	client := h2cClient()
+	resp, err := client.Get(base + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(base + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, resp.ProtoMajor, "HTTP/1.1 clients are still served")
+
+	// HTTP/1.1 clients may upgrade to h2c
+	conn, err := net.Dial("tcp", runner.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("GET /ping HTTP/1.1\r\nHost: solanum\r\nConnection: Upgrade, HTTP2-Settings\r\n" +
+		"Upgrade: h2c\r\nHTTP2-Settings: AAMAAABkAARAAAAAAAIAAAAA\r\n\r\n"))
+	require.NoError(t, err)
+
+	upgraded, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, upgraded.StatusCode)
+	assert.Equal(t, "h2c", upgraded.Header.Get("Upgrade"))
+
+	resp, err = client.Get(base + "/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var events []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+
+		if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+
+			events = append(events, data)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"0 HTTP/2.0", "1 HTTP/2.0", "2 HTTP/2.0"}, events)
+}
+
+// TestShutdownDrainsH2C verifies Shutdown sends a GOAWAY on open h2c connections, waits
+// for the requests in flight on them, and leaves them closed.
+func TestShutdownDrainsH2C(t *testing.T) {
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		close(entered)
+		<-release
+		c.String(http.StatusOK, "done")
+	}})
+	m := solanum.NewModule(solanum.WithUri("/slow"))
+	m.SetControllers(ctr)
+
+	runner := solanum.New(solanum.WithHost("127.0.0.1"), solanum.WithH2C())
+	runner.SetModules(m)
+	runner = startPing(t, runner)
+	base := "http://" + runner.Addr().String()
+
+	conn, err := net.Dial("tcp", runner.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cc, err := (&http2.Transport{AllowHTTP: true}).NewClientConn(conn)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, base+"/ping", nil)
+	require.NoError(t, err)
+	resp, err := cc.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, resp.ProtoMajor)
+
+	type result struct {
+		body string
+		err  error
+	}
+	slow := make(chan result, 1)
+	go func() {
+
+		req, _ := http.NewRequest(http.MethodGet, base+"/slow", nil)
+		resp, err := cc.RoundTrip(req)
+		if err != nil {
+
+			slow <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		slow <- result{body: string(body), err: err}
+	}()
+	<-entered
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- runner.Shutdown(context.Background()) }()
+
+	assert.Eventually(t, func() bool { return cc.State().Closing }, time.Second, 10*time.Millisecond,
+		"the h2c connection gets a GOAWAY")
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned before the in-flight h2c request completed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	res := <-slow
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+
+	select {
+	case err := <-shutdown:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return once the h2c request completed")
+	}
+
+	assert.Eventually(t, func() bool { return cc.State().Closed }, time.Second, 10*time.Millisecond,
+		"the drained h2c connection is closed")
+}
+
+// TestWithoutH2C verifies cleartext HTTP/2 is refused unless WithH2C is set.
+func TestWithoutH2C(t *testing.T) {
+
+	runner := startPing(t, solanum.New(solanum.WithHost("127.0.0.1")))
+
+	_, err := h2cClient().Get("http://" + runner.Addr().String() + "/ping")
+	assert.Error(t, err)
+}
+
+// TestHTTP2OverTLS verifies HTTP/2 is negotiated whenever TLS is configured.
+func TestHTTP2OverTLS(t *testing.T) {
+
+	certFile, keyFile, _, pool := selfSignedCert(t)
+	runner := startPing(t, solanum.New(solanum.WithHost("127.0.0.1"), solanum.WithTLS(certFile, keyFile)))
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool},
+		ForceAttemptHTTP2: true,
+	}}
+
+	resp, err := client.Get("https://" + runner.Addr().String() + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, "h2", resp.TLS.NegotiatedProtocol)
+}
@@ -8,9 +8,12 @@ import (
 
 // Compile-time checks that core types satisfy their interfaces.
 var (
-	_ solanum.Module     = (*solanum.SolaModule)(nil)
-	_ solanum.Controller = (*solanum.SolaController)(nil)
-	_ solanum.Runner     = *solanum.NewSolanum(5050)
+	_ solanum.Module           = (*solanum.SolaModule)(nil)
+	_ solanum.Controller       = (*solanum.SolaController)(nil)
+	_ solanum.RoutedController = (*solanum.SolaController)(nil)
+	_ solanum.ModuleStarter    = (*solanum.SolaModule)(nil)
+	_ solanum.ModuleStopper    = (*solanum.SolaModule)(nil)
+	_ solanum.Runner           = solanum.NewSolanum(solanum.WithPort(5050))
 )
 
 // Dummy test to ensure this file is included in test suite.
@@ -0,0 +1,150 @@
+package solanum_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+)
+
+// echoModule returns a module at uri echoing request bodies.
+func echoModule(uri string) *solanum.SolaModule {
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(
+		&solanum.SolaService{Uri: "/raw", Method: http.MethodPost, Handler: func(c *gin.Context) {
+
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+
+				_ = c.Error(err)
+				return
+			}
+			c.String(http.StatusOK, string(body))
+		}},
+		solanum.Handle(http.MethodPost, "/typed", func(ctx context.Context, req struct {
+			Text string `json:"text"`
+		}) (string, error) {
+
+			return req.Text, nil
+		}),
+	)
+
+	m := solanum.NewModule(solanum.WithUri(uri))
+	m.SetControllers(ctr)
+
+	return m
+}
+
+// TestBodyLimit verifies the runner's body limit, its per-module override, and that
+// oversized bodies are rejected with 413 whether or not they declare their length.
+func TestBodyLimit(t *testing.T) {
+
+	small := echoModule("/notes")
+	upload := solanum.NewModule(solanum.WithUri("/upload"), solanum.WithModuleMaxBodyBytes(64))
+	upload.AddSubModules(echoModule("/files"))
+	unlimited := echoModule("/bulk")
+	require.NoError(t, solanum.WithModuleMaxBodyBytes(-1)(unlimited))
+
+	runner := solanum.New(solanum.WithMaxBodyBytes(16))
+	runner.SetModules(small, upload, unlimited)
+	runner.InitModules()
+
+	serve := func(path string, body string, chunked bool) *httptest.ResponseRecorder {
+
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if chunked {
+
+			req.ContentLength = -1
+		}
+
+		rec := httptest.NewRecorder()
+		runner.GinEngine().ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := serve("/notes/raw", "short", false)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "short", rec.Body.String())
+
+	rec = serve("/notes/raw", strings.Repeat("x", 17), false)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, solanum.MIMEProblemJSON, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"code":"body_too_large"`)
+	assert.Contains(t, rec.Body.String(), "the request body exceeds 16 bytes")
+
+	rec = serve("/notes/raw", strings.Repeat("x", 17), true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "bodies without length are cut at the limit")
+
+	rec = serve("/notes/typed", `{"text":"`+strings.Repeat("x", 20)+`"}`, true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "typed handlers report 413, not 400")
+
+	rec = serve("/upload/files/raw", strings.Repeat("x", 64), false)
+	assert.Equal(t, http.StatusOK, rec.Code, "sub-modules inherit the module's limit")
+
+	rec = serve("/upload/files/raw", strings.Repeat("x", 65), true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = serve("/bulk/raw", strings.Repeat("x", 1024), false)
+	assert.Equal(t, http.StatusOK, rec.Code, "a negative module limit removes the limit")
+
+	middlewares, err := runner.Middlewares()
+	require.NoError(t, err)
+	assert.Contains(t, middlewares, solanum.MiddlewareBodyLimit)
+}
+
+// TestServerTimeoutsAndHeaderLimit verifies the read header timeout closes slow
+// connections and oversized headers are rejected.
+func TestServerTimeoutsAndHeaderLimit(t *testing.T) {
+
+	runner := solanum.New(
+		solanum.WithHost("127.0.0.1"),
+		solanum.WithReadHeaderTimeout(100*time.Millisecond),
+		solanum.WithReadTimeout(time.Second),
+		solanum.WithWriteTimeout(time.Second),
+		solanum.WithIdleTimeout(time.Second),
+		solanum.WithMaxHeaderBytes(1024),
+	)
+	runner.SetModules(echoModule("/notes"))
+	require.NoError(t, runner.Start(context.Background()))
+	defer runner.Shutdown(context.Background())
+
+	// A client sending its headers too slowly is disconnected
+	conn, err := net.Dial("tcp", runner.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("POST /notes/raw HTTP/1.1\r\nHost: solanum\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	start := time.Now()
+	_, err = io.ReadAll(conn)
+	assert.NoError(t, err, "the server closes the connection")
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Headers over the limit are refused
+	conn, err = net.Dial("tcp", runner.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("GET /notes/raw HTTP/1.1\r\nHost: solanum\r\nX-Big: " + strings.Repeat("x", 8192) + "\r\n\r\n"))
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+}
@@ -0,0 +1,207 @@
+package solanum_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+)
+
+// selfSignedCert writes a self-signed certificate for 127.0.0.1, usable by servers and
+// clients, to PEM files in a temporary directory.
+func selfSignedCert(t *testing.T) (certFile, keyFile string, cert tls.Certificate, pool *x509.CertPool) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "solanum-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	pool = x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+
+	return certFile, keyFile, cert, pool
+}
+
+// startPing starts runner serving GET /ping, answering with the common name of the
+// client certificate, if any.
+func startPing(t *testing.T, runner solanum.Runner) solanum.Runner {
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		if c.Request.TLS != nil && len(c.Request.TLS.PeerCertificates) > 0 {
+
+			c.String(http.StatusOK, "pong "+c.Request.TLS.PeerCertificates[0].Subject.CommonName)
+			return
+		}
+		c.String(http.StatusOK, "pong")
+	}})
+	m := solanum.NewModule(solanum.WithUri("/ping"))
+	m.SetControllers(ctr)
+	runner.SetModules(m)
+
+	require.NoError(t, runner.Start(context.Background()))
+	t.Cleanup(func() { _ = runner.Shutdown(context.Background()) })
+
+	return runner
+}
+
+// get requests url with client and returns the body.
+func get(t *testing.T, client *http.Client, url string) string {
+
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+// TestWithHost verifies the server listens on the given host.
+func TestWithHost(t *testing.T) {
+
+	runner := startPing(t, solanum.New(solanum.WithHost("127.0.0.1")))
+
+	addr := runner.Addr().(*net.TCPAddr)
+	assert.Equal(t, "127.0.0.1", addr.IP.String())
+	assert.Equal(t, "pong", get(t, http.DefaultClient, "http://"+addr.String()+"/ping"))
+}
+
+// TestWithTLS verifies HTTPS with certificate files, and mutual TLS with a config
+// requiring client certificates.
+func TestWithTLS(t *testing.T) {
+
+	certFile, keyFile, cert, pool := selfSignedCert(t)
+
+	runner := startPing(t, solanum.New(solanum.WithHost("127.0.0.1"), solanum.WithTLS(certFile, keyFile)))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	assert.Equal(t, "pong", get(t, client, "https://"+runner.Addr().String()+"/ping"))
+
+	resp, err := http.Get("http://" + runner.Addr().String() + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "plain HTTP is refused")
+
+	mtls := startPing(t, solanum.New(
+		solanum.WithHost("127.0.0.1"),
+		solanum.WithTLSConfig(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    pool,
+		}),
+	))
+	url := "https://" + mtls.Addr().String() + "/ping"
+
+	_, err = client.Get(url)
+	assert.Error(t, err, "a client without certificate is rejected")
+
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+	}}}
+	assert.Equal(t, "pong solanum-test", get(t, client, url))
+}
+
+// TestWithTLSInvalidCertificate verifies Start fails on unreadable certificates.
+func TestWithTLSInvalidCertificate(t *testing.T) {
+
+	runner := solanum.New(solanum.WithTLS("missing.pem", "missing-key.pem"))
+
+	err := runner.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fail to load TLS certificate")
+	assert.Nil(t, runner.Addr())
+}
+
+// TestWithUnixSocket verifies the server serves on a Unix socket, replacing a stale one.
+func TestWithUnixSocket(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "solanum.sock")
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	runner := startPing(t, solanum.New(solanum.WithUnixSocket(path)))
+	assert.Equal(t, "unix", runner.Addr().Network())
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	assert.Equal(t, "pong", get(t, client, "http://solanum/ping"))
+
+	require.NoError(t, runner.Shutdown(context.Background()))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "the socket file is removed on shutdown")
+}
+
+// TestWithListener verifies the server serves on a given listener, for a single Start.
+func TestWithListener(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	runner := startPing(t, solanum.New(solanum.WithListener(ln)))
+	assert.Equal(t, ln.Addr(), runner.Addr())
+	assert.Equal(t, "pong", get(t, http.DefaultClient, "http://"+ln.Addr().String()+"/ping"))
+
+	conflicting := solanum.New(solanum.WithListener(ln), solanum.WithUnixSocket("solanum.sock"))
+	assert.ErrorContains(t, conflicting.Start(context.Background()), "conflicting listener options")
+
+	// The listener is closed on Shutdown, so it cannot serve another Start
+	require.NoError(t, runner.Shutdown(context.Background()))
+	assert.ErrorContains(t, runner.Start(context.Background()), "listener already used")
+
+	next, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	solanum.WithListener(next)(runner)
+	require.NoError(t, runner.Start(context.Background()))
+	assert.Equal(t, "pong", get(t, http.DefaultClient, "http://"+next.Addr().String()+"/ping"))
+}
@@ -0,0 +1,111 @@
+package solanum_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// TestRunnerLoggerAndAccessLog verifies the logger is registered in the container
+// and access logs carry request details, with sensitive values redacted.
+func TestRunnerLoggerAndAccessLog(t *testing.T) {
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "/:id", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		c.String(http.StatusOK, "ok")
+	}})
+	m := solanum.NewModule(solanum.WithUri("/orders"))
+	m.SetControllers(ctr)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(5054),
+		solanum.WithLogger(logger),
+		solanum.WithAccessLog(
+			solanum.WithLoggedHeaders("Authorization", "X-Client"),
+			solanum.WithRedactedQuery("token"),
+		),
+	)
+	runner.SetModules(m)
+
+	registered, err := runner.Container().Resolve(solanum.LoggerKey)
+	require.NoError(t, err)
+	assert.Same(t, logger, registered)
+	assert.Same(t, logger, runner.Logger())
+
+	runner.InitModules()
+	assert.Contains(t, logs.String(), `msg="initializing modules" modules=1`)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/7?page=2&token=s3cret", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	req.Header.Set("X-Client", "web")
+	req.Header.Set(solanum.HeaderRequestID, "req-7")
+	runner.GinEngine().ServeHTTP(httptest.NewRecorder(), req)
+
+	line := logs.String()
+	assert.Contains(t, line, "level=INFO msg=request method=GET route=/orders/:id path=/orders/7 status=200")
+	assert.Contains(t, line, "bytes=2 client_ip=192.0.2.1 request_id=req-7 query=\"page=2&token=REDACTED\"")
+	assert.Contains(t, line, "headers.Authorization=REDACTED headers.X-Client=web")
+	assert.NotContains(t, line, "s3cret")
+}
+
+// TestWithLoggerKeepsApplicationLogger verifies a logger the application registered
+// under LoggerKey is left alone, while the runner's own registration is kept up to date.
+func TestWithLoggerKeepsApplicationLogger(t *testing.T) {
+
+	appLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runnerLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	c := container.New()
+	c.Register(solanum.LoggerKey, appLogger)
+
+	runner := solanum.New(solanum.WithContainer(c), solanum.WithLogger(runnerLogger))
+	assert.Same(t, runnerLogger, runner.Logger())
+
+	registered, err := c.Resolve(solanum.LoggerKey)
+	require.NoError(t, err)
+	assert.Same(t, appLogger, registered)
+
+	// A runner replaces the logger it registered itself
+	owned := container.New()
+	solanum.SolanumRunner = nil
+	t.Cleanup(func() { solanum.SolanumRunner = nil })
+
+	solanum.NewSolanum(solanum.WithPort(5055), solanum.WithContainer(owned))
+	solanum.NewSolanum(solanum.WithLogger(runnerLogger))
+
+	registered, err = owned.Resolve(solanum.LoggerKey)
+	require.NoError(t, err)
+	assert.Same(t, runnerLogger, registered)
+}
+
+// TestAccessLogSampling verifies sampling keeps server errors.
+func TestAccessLogSampling(t *testing.T) {
+
+	var logs bytes.Buffer
+
+	r := gin.New()
+	r.Use(solanum.AccessLog(slog.New(slog.NewTextHandler(&logs, nil)), solanum.WithSampleRate(0)))
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Empty(t, logs.String())
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Contains(t, logs.String(), "level=ERROR msg=request method=GET route=/fail")
+}
@@ -0,0 +1,88 @@
+package solanum_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// TestRunnerMetrics verifies WithMetrics records requests by module, route and status,
+// observes dependency resolution, and serves everything at /metrics.
+func TestRunnerMetrics(t *testing.T) {
+
+	c := container.New()
+	c.Register("repo", func() (string, error) { return "orders", nil }, container.WithSingleton())
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "/:id", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		c.String(http.StatusOK, "ok")
+	}})
+	m := solanum.NewModule(solanum.WithUri("/orders"))
+	m.SetDependencies(*container.DepConfig[string]("repo"))
+	m.SetControllers(ctr)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(5055),
+		solanum.WithContainer(c),
+		solanum.WithMetrics("/metrics"),
+	)
+	runner.SetModules(m)
+	runner.InitModules()
+
+	for _, path := range []string{"/orders/1", "/orders/2"} {
+
+		rec := httptest.NewRecorder()
+		runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE solanum_http_requests_total counter\n")
+	assert.Contains(t, body, `solanum_http_requests_total{module="/orders",route="/orders/:id",method="GET",status="200"} 2`+"\n")
+	assert.Contains(t, body, `solanum_http_request_duration_seconds_bucket{module="/orders",route="/orders/:id",method="GET",status="200",le="+Inf"} 2`+"\n")
+	assert.Contains(t, body, `solanum_http_request_duration_seconds_count{module="/orders",route="/orders/:id",method="GET",status="200"} 2`+"\n")
+	assert.Contains(t, body, `solanum_http_requests_in_flight{module="/metrics",route="/metrics",method="GET"} 1`+"\n")
+	assert.Contains(t, body, `solanum_container_resolves_total{key="repo",result="ok"} 2`+"\n")
+	assert.Contains(t, body, `solanum_container_resolve_duration_seconds_count{key="repo"} 2`+"\n")
+	assert.Contains(t, body, `solanum_container_singleton_construction_seconds{key="repo"} `)
+}
+
+// TestMetricsExposition verifies histogram buckets are cumulative and label values escaped.
+func TestMetricsExposition(t *testing.T) {
+
+	metrics := solanum.NewMetrics()
+
+	r := gin.New()
+	r.Use(metrics.Middleware())
+	r.GET("/items/*path", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/a", nil))
+
+	metrics.ObserveResolve(`a"b`, 0, assert.AnError)
+
+	var out bytes.Buffer
+	n, err := metrics.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(out.Len()), n)
+
+	body := out.String()
+	assert.Contains(t, body, `solanum_http_requests_total{module="",route="/items/*path",method="GET",status="404"} 1`+"\n")
+	assert.Contains(t, body, `solanum_http_request_duration_seconds_bucket{module="",route="/items/*path",method="GET",status="404",le="10"} 1`+"\n")
+	assert.Contains(t, body, `solanum_container_resolves_total{key="a\"b",result="error"} 1`+"\n")
+	assert.Contains(t, body, `solanum_container_resolve_duration_seconds_bucket{key="a\"b",le="0.005"} 1`+"\n")
+	assert.NotContains(t, body, "solanum_container_singleton_construction_seconds")
+}
@@ -0,0 +1,101 @@
+package solanum_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+)
+
+// showOrder is the handler of the routes used to inspect middleware chains.
+func showOrder(c *gin.Context) {
+
+	c.String(http.StatusOK, strings.Join(c.GetStringSlice("trace"), ","))
+}
+
+// traceAs returns a global middleware recording its name in the request.
+func traceAs(name string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		c.Set("trace", append(c.GetStringSlice("trace"), name))
+	}
+}
+
+// TestGlobalMiddlewareRegistry verifies ordering, path exclusion and chain listing.
+func TestGlobalMiddlewareRegistry(t *testing.T) {
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(
+		&solanum.SolaService{Uri: "/:id", Method: http.MethodGet, Handler: showOrder},
+		&solanum.SolaService{Uri: "/internal/ping", Method: http.MethodGet, Handler: showOrder},
+	)
+	m := solanum.NewModule(solanum.WithUri("/orders"))
+	m.SetControllers(ctr)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(5053))
+	runner.SetModules(m)
+
+	require.NoError(t, runner.Use("auth", traceAs("auth"), solanum.WithPriority(10)))
+	require.NoError(t, runner.Use("audit", traceAs("audit"), solanum.RunAfter("auth"), solanum.WithPriority(-100)))
+	require.NoError(t, runner.Use("tracing", traceAs("tracing"), solanum.RunBefore(solanum.MiddlewareErrors)))
+	require.NoError(t, runner.Use("metrics", traceAs("metrics"), solanum.WithExcludePaths("/healthz", "/orders/internal/*")))
+	assert.Error(t, runner.Use("auth", traceAs("auth")), "names are unique")
+
+	names, err := runner.Middlewares()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		solanum.MiddlewareRequestID,
+		solanum.MiddlewareRecovery,
+		"tracing",
+		solanum.MiddlewareErrors,
+		"metrics",
+		"auth",
+		"audit",
+	}, names)
+
+	runner.InitModules()
+	assert.Error(t, runner.Use("late", traceAs("late")), "middlewares are applied with the modules")
+
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	assert.Equal(t, "tracing,metrics,auth,audit", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/internal/ping", nil))
+	assert.Equal(t, "tracing,auth,audit", rec.Body.String())
+
+	chain, err := runner.Chain(http.MethodGet, "/orders/:id")
+	require.NoError(t, err)
+	require.Len(t, chain, 9)
+	assert.Equal(t, names, chain[:7])
+	assert.Contains(t, chain[7], "serviceContext")
+	assert.Contains(t, chain[8], "showOrder")
+
+	_, err = runner.Chain(http.MethodGet, "/missing")
+	assert.Error(t, err)
+}
+
+// TestGlobalMiddlewareCycle verifies contradicting constraints abort startup.
+func TestGlobalMiddlewareCycle(t *testing.T) {
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(freePort(t)))
+
+	require.NoError(t, runner.Use("a", traceAs("a"), solanum.RunBefore("b")))
+	require.NoError(t, runner.Use("b", traceAs("b"), solanum.RunBefore("a")))
+
+	_, err := runner.Middlewares()
+	assert.ErrorContains(t, err, "cycle between a, b")
+
+	err = runner.Start(context.Background())
+	assert.ErrorContains(t, err, "global middleware check failed")
+}
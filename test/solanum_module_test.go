@@ -32,12 +32,12 @@ func init() {
 
 // TestNewModuleImplementsModule ensures NewModule returns a Module.
 func TestNewModuleImplementsModule(t *testing.T) {
-	var _ solanum.Module = solanum.NewModule("/test")
+	var _ solanum.Module = solanum.NewModule(solanum.WithUri("/test"))
 }
 
 // TestMiddlewareChains validates pre- and post-middleware management.
 func TestMiddlewareChains(t *testing.T) {
-	m := solanum.NewModule("/")
+	m := solanum.NewModule(solanum.WithUri("/"))
 
 	// Replace and count pre-middlewares
 	m.SetPreMiddlewares(func(c *gin.Context) {}, func(c *gin.Context) {})
@@ -58,7 +58,7 @@ func TestMiddlewareChains(t *testing.T) {
 
 // TestControllersAndDependencies ensures controllers and dependencies can be registered.
 func TestControllersAndDependencies(t *testing.T) {
-	m := solanum.NewModule("/")
+	m := solanum.NewModule(solanum.WithUri("/"))
 
 	// Controller registration
 	ctrl := solanum.NewController()
@@ -67,7 +67,7 @@ func TestControllersAndDependencies(t *testing.T) {
 	assert.Len(t, m.Controllers(), 1)
 
 	// Dependency configuration
-	dc := container.Dep[string]("key")
+	dc := container.DepConfig[string]("key")
 	m.SetDependencies(*dc)
 	assert.Len(t, *m.Dependencies(), 1)
 }
@@ -78,7 +78,7 @@ func TestSetRoutesWithoutDependencies(t *testing.T) {
 	// Standalone route to verify Gin works
 	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
 
-	m := solanum.NewModule("/api")
+	m := solanum.NewModule(solanum.WithUri("/api"))
 	ctrl := solanum.NewController()
 	ctrl.SetHandlers(&solanum.SolaService{Uri: "/ping", Method: "GET", Handler: func(c *gin.Context) { c.String(http.StatusOK, "ok") }})
 	m.SetControllers(ctrl)
@@ -100,8 +100,8 @@ func TestSetRoutesWithDependencies(t *testing.T) {
 	)
 
 	r := gin.New()
-	m := solanum.NewModule("/api")
-	m.SetDependencies(*container.Dep[FooService]("foo"))
+	m := solanum.NewModule(solanum.WithUri("/api"))
+	m.SetDependencies(*container.DepConfig[FooService]("foo"))
 
 	// Handler uses injected service
 	ctrl := solanum.NewController()
@@ -109,7 +109,7 @@ func TestSetRoutesWithDependencies(t *testing.T) {
 		Uri:    "/dep",
 		Method: "GET",
 		Handler: func(c *gin.Context) {
-			d := container.DepFromContext[FooService](c, "foo")
+			d := container.DepFromGinContext[FooService](c, "foo")
 			c.String(http.StatusOK, d.Foo())
 		},
 	})
@@ -121,3 +121,377 @@ func TestSetRoutesWithDependencies(t *testing.T) {
 	r.ServeHTTP(rec, req)
 	assert.Equal(t, "bar", rec.Body.String())
 }
+
+// requestState is a per-request value used to verify scoped injection.
+type requestState struct{ closed bool }
+
+// Close implements io.Closer so that scope disposal can be observed.
+func (s *requestState) Close() error {
+	s.closed = true
+	return nil
+}
+
+// TestSetRoutesWithScopedDependency verifies each request gets its own scoped instance,
+// shared by every resolve within that request and disposed when the request ends.
+func TestSetRoutesWithScopedDependency(t *testing.T) {
+	container.Register("requestState", func() *requestState { return &requestState{} }, container.WithScoped())
+
+	r := gin.New()
+	m := solanum.NewModule(solanum.WithUri("/api"))
+	m.SetDependencies(*container.DepConfig[*requestState]("requestState"))
+
+	var seen []*requestState
+	ctrl := solanum.NewController()
+	ctrl.SetHandlers(&solanum.SolaService{
+		Uri:    "/scoped",
+		Method: "GET",
+		Handler: func(c *gin.Context) {
+			injected := container.DepFromGinContext[*requestState](c, "requestState")
+			resolved, err := container.ResolveContext(c.Request.Context(), "requestState")
+			assert.NoError(t, err)
+			assert.Same(t, injected, resolved)
+
+			seen = append(seen, injected)
+			c.Status(http.StatusNoContent)
+		},
+	})
+	m.SetControllers(ctrl)
+	m.SetRoutes(r.Group("/api"))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/scoped", nil))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+
+	assert.Len(t, seen, 2)
+	assert.NotSame(t, seen[0], seen[1])
+	assert.True(t, seen[0].closed)
+	assert.True(t, seen[1].closed)
+}
+
+// TestSetRoutesWithModuleContainer verifies DI resolves against the module's own container.
+func TestSetRoutesWithModuleContainer(t *testing.T) {
+	c := container.New()
+	c.Register("greeting", "hello from module container")
+
+	r := gin.New()
+	m := solanum.NewModule(
+		solanum.WithUri("/api"),
+		solanum.WithModuleContainer(c),
+		solanum.WithDependency(container.DepConfig[string]("greeting")),
+	)
+	assert.Same(t, c, m.Container())
+
+	ctrl := solanum.NewController()
+	ctrl.SetHandlers(&solanum.SolaService{
+		Uri:    "/greeting",
+		Method: "GET",
+		Handler: func(c *gin.Context) {
+			c.String(http.StatusOK, container.DepFromGinContext[string](c, "greeting"))
+		},
+	})
+	m.SetControllers(ctrl)
+	m.SetRoutes(r.Group("/api"))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/greeting", nil))
+	assert.Equal(t, "hello from module container", rec.Body.String())
+}
+
+// TestSetRoutesWithChildContainerOverride verifies a module can override a key through
+// a child container while other modules keep resolving the parent's provider.
+func TestSetRoutesWithChildContainerOverride(t *testing.T) {
+	app := container.New()
+	app.Register("httpClient", "default client")
+
+	billing := app.NewChild()
+	billing.Register("httpClient", "billing client")
+
+	newModule := func(uri string, c *container.Container) *solanum.SolaModule {
+		m := solanum.NewModule(
+			solanum.WithUri(uri),
+			solanum.WithModuleContainer(c),
+			solanum.WithDependency(container.DepConfig[string]("httpClient")),
+		)
+
+		ctrl := solanum.NewController()
+		ctrl.SetHandlers(&solanum.SolaService{
+			Uri:    "/client",
+			Method: "GET",
+			Handler: func(c *gin.Context) {
+				c.String(http.StatusOK, container.DepFromGinContext[string](c, "httpClient"))
+			},
+		})
+		m.SetControllers(ctrl)
+
+		return m
+	}
+
+	r := gin.New()
+	for _, m := range []*solanum.SolaModule{newModule("/billing", billing), newModule("/users", app)} {
+		m.SetRoutes(r.Group(m.Uri()))
+	}
+
+	for path, want := range map[string]string{
+		"/billing/client": "billing client",
+		"/users/client":   "default client",
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, want, rec.Body.String(), path)
+	}
+}
+
+// generatedController is a hand-written Controller that is not a *SolaController.
+type generatedController struct{}
+
+// Handlers implements Controller.
+func (generatedController) Handlers() []*solanum.SolaService {
+	return []*solanum.SolaService{{
+		Uri:     "/generated",
+		Method:  http.MethodGet,
+		Handler: func(c *gin.Context) { c.String(http.StatusOK, "generated") },
+	}}
+}
+
+// SetHandlers implements Controller; generated controllers are immutable.
+func (generatedController) SetHandlers(...*solanum.SolaService) {}
+
+// adminController embeds *SolaController and contributes a prefix and middleware.
+type adminController struct {
+	*solanum.SolaController
+}
+
+// Prefix implements RoutedController.
+func (adminController) Prefix() string { return "/admin" }
+
+// Middlewares implements RoutedController.
+func (adminController) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{func(c *gin.Context) {
+		c.Header("X-Trace", c.GetHeader("X-Trace")+"controller;")
+	}}
+}
+
+// TestSetRoutesWithCustomControllers verifies any Controller implementation is mounted, and
+// that RoutedController prefixes and middleware are applied between pre-middleware and handler.
+func TestSetRoutesWithCustomControllers(t *testing.T) {
+	admin := adminController{solanum.NewController()}
+	admin.SetHandlers(&solanum.SolaService{
+		Uri:    "/stats",
+		Method: http.MethodGet,
+		Handler: func(c *gin.Context) {
+			c.String(http.StatusOK, c.Writer.Header().Get("X-Trace"))
+		},
+	})
+
+	m := solanum.NewModule(solanum.WithUri("/api"))
+	m.SetPreMiddlewares(func(c *gin.Context) { c.Request.Header.Set("X-Trace", "pre;") })
+	m.SetControllers(generatedController{}, admin)
+
+	r := gin.New()
+	assert.NotPanics(t, func() { m.SetRoutes(r.Group("/api")) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/generated", nil))
+	assert.Equal(t, "generated", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/admin/stats", nil))
+	assert.Equal(t, "pre;controller;", rec.Body.String())
+
+	// The prefix only applies to the routed controller
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/stats", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// requireRoles is a pre-middleware that enforces the service's MetaRoles.
+func requireRoles(c *gin.Context) {
+	svc := solanum.ServiceFromContext(c)
+	if roles, ok := svc.Meta[solanum.MetaRoles].([]string); ok {
+		for _, role := range roles {
+			if c.GetHeader("X-Role") != role {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+	}
+	c.Next()
+}
+
+// TestSetRoutesWithServiceMiddlewaresAndMeta verifies the per-service chain order and metadata access.
+func TestSetRoutesWithServiceMiddlewaresAndMeta(t *testing.T) {
+	trace := func(step string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Request.Header.Add("X-Trace", step) }
+	}
+
+	var post []string
+	m := solanum.NewModule(solanum.WithUri("/api"))
+	m.SetPreMiddlewares(requireRoles, trace("pre"))
+	m.SetPostMiddlewares(func(c *gin.Context) { post = c.Request.Header.Values("X-Trace") })
+
+	ctrl := solanum.NewController()
+	ctrl.SetHandlers(
+		&solanum.SolaService{
+			Uri:         "/secret",
+			Method:      http.MethodGet,
+			Middlewares: []gin.HandlerFunc{trace("service")},
+			Meta: map[string]any{
+				solanum.MetaName:  "getSecret",
+				solanum.MetaRoles: []string{"admin"},
+			},
+			Handler: func(c *gin.Context) {
+				c.Request.Header.Add("X-Trace", "handler")
+				c.String(http.StatusOK, solanum.ServiceFromContext(c).Meta[solanum.MetaName].(string))
+			},
+		},
+		&solanum.SolaService{
+			Uri:     "/public",
+			Method:  http.MethodGet,
+			Handler: func(c *gin.Context) { c.String(http.StatusOK, "public") },
+		},
+	)
+	m.SetControllers(ctrl)
+
+	r := gin.New()
+	m.SetRoutes(r.Group("/api"))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/secret", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req := httptest.NewRequest("GET", "/api/secret", nil)
+	req.Header.Set("X-Role", "admin")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, "getSecret", rec.Body.String())
+	assert.Equal(t, []string{"pre", "service", "handler"}, post)
+
+	// Service middleware does not leak into other services
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/public", nil))
+	assert.Equal(t, "public", rec.Body.String())
+	assert.Equal(t, []string{"pre"}, post)
+}
+
+// TestRunnerRoutes verifies routes are listed with full paths and metadata.
+func TestRunnerRoutes(t *testing.T) {
+	ctrl := solanum.NewController()
+	ctrl.SetPrefix("/admin")
+	ctrl.SetHandlers(&solanum.SolaService{
+		Uri:     "/:id",
+		Method:  http.MethodDelete,
+		Meta:    map[string]any{solanum.MetaSummary: "Delete a user"},
+		Handler: func(c *gin.Context) {},
+	})
+
+	m := solanum.NewModule(solanum.WithUri("/users"))
+	m.SetControllers(ctrl)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(5050))
+	runner.SetModules(m)
+
+	routes := runner.Routes()
+	if assert.Len(t, routes, 1) {
+		assert.Equal(t, http.MethodDelete, routes[0].Method)
+		assert.Equal(t, "/users/admin/:id", routes[0].Path)
+		assert.Equal(t, "Delete a user", routes[0].Service.Meta[solanum.MetaSummary])
+	}
+}
+
+// TestNestedModules verifies sub-modules mount below their parent, inherit its middleware
+// and dependencies, and can override inherited dependencies.
+func TestNestedModules(t *testing.T) {
+	c := container.New()
+	c.Register("version", "v1")
+	c.Register("greeting", "hello")
+
+	orderGreeting := c.NewChild()
+	orderGreeting.Register("greeting", "hello from orders")
+
+	var trace []string
+	step := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { trace = append(trace, name) }
+	}
+	handler := func(c *gin.Context) {
+		trace = append(trace, "handler")
+		c.String(http.StatusOK, "%s %s",
+			container.DepFromGinContext[string](c, "version"),
+			container.DepFromGinContext[string](c, "greeting"),
+		)
+	}
+
+	api := solanum.NewModule(solanum.WithUri("/api/v1"), solanum.WithModuleContainer(c))
+	api.SetDependencies(*container.DepConfig[string]("version"), *container.DepConfig[string]("greeting"))
+	api.SetPreMiddlewares(step("api-pre"))
+	api.SetPostMiddlewares(step("api-post"))
+
+	users := solanum.NewModule(solanum.WithUri("/users"))
+	users.SetPreMiddlewares(step("users-pre"))
+	users.SetPostMiddlewares(step("users-post"))
+	usersCtrl := solanum.NewController()
+	usersCtrl.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: handler})
+	users.SetControllers(usersCtrl)
+
+	orders := solanum.NewModule(solanum.WithUri("/orders"), solanum.WithModuleContainer(orderGreeting))
+	orders.SetDependencies(*container.DepConfig[string]("greeting"))
+	ordersCtrl := solanum.NewController()
+	ordersCtrl.SetHandlers(&solanum.SolaService{Uri: "", Method: http.MethodGet, Handler: handler})
+	orders.SetControllers(ordersCtrl)
+
+	api.AddSubModules(users, orders)
+	assert.Len(t, api.SubModules(), 2)
+	assert.Same(t, c, users.Container(), "sub-modules inherit the parent's container")
+
+	r := gin.New()
+	api.SetRoutes(r.Group(api.Uri()))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/users", nil))
+	assert.Equal(t, "v1 hello", rec.Body.String())
+	assert.Equal(t, []string{"api-pre", "users-pre", "handler", "users-post", "api-post"}, trace)
+
+	trace = nil
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/orders", nil))
+	assert.Equal(t, "v1 hello from orders", rec.Body.String())
+	assert.Equal(t, []string{"api-pre", "handler", "api-post"}, trace)
+
+	// Runner.Routes walks sub-modules with their full paths
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(5050), solanum.WithContainer(c))
+	runner.SetModules(api)
+
+	var paths []string
+	for _, route := range runner.Routes() {
+		paths = append(paths, route.Path)
+	}
+	assert.Equal(t, []string{"/api/v1/users", "/api/v1/orders"}, paths)
+	assert.NoError(t, runner.ValidateDependencies())
+}
+
+// TestWithDependencyScoped verifies a module can declare a scoped dependency: it is
+// checked within a throwaway scope, whose instance is disposed right away.
+func TestWithDependencyScoped(t *testing.T) {
+	var built []*requestState
+	c := container.New()
+	c.Register("req", func() *requestState {
+		s := &requestState{}
+		built = append(built, s)
+		return s
+	}, container.WithScoped())
+
+	var m *solanum.SolaModule
+	assert.NotPanics(t, func() {
+		m = solanum.NewModule(
+			solanum.WithUri("/api"),
+			solanum.WithModuleContainer(c),
+			solanum.WithDependency(container.DepConfig[*requestState]("req")),
+		)
+	})
+	assert.Len(t, *m.Dependencies(), 1)
+	assert.Len(t, built, 1)
+	assert.True(t, built[0].closed)
+}
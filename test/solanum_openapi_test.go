@@ -0,0 +1,152 @@
+package solanum_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+)
+
+// apiUser is a response type documented as a component schema.
+type apiUser struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Friends   []apiUser `json:"friends,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	internal  string
+}
+
+// getUser is a request type made of parameters only.
+type getUser struct {
+	ID      int    `uri:"id"`
+	Verbose bool   `form:"verbose"`
+	Tenant  string `header:"X-Tenant" binding:"required"`
+}
+
+// createUser is a request type made of a JSON body only.
+type createUser struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email,omitempty"`
+	Token string `json:"-"`
+}
+
+// TestOpenAPIDocument verifies the document generated from modules, controllers and services.
+func TestOpenAPIDocument(t *testing.T) {
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(
+		&solanum.SolaService{
+			Uri:      "/:id",
+			Method:   http.MethodGet,
+			Handler:  func(*gin.Context) {},
+			Request:  getUser{},
+			Response: apiUser{},
+			Meta: map[string]any{
+				solanum.MetaName:    "getUser",
+				solanum.MetaSummary: "Get a user",
+				solanum.MetaTags:    []string{"users"},
+			},
+		},
+		&solanum.SolaService{
+			Uri:      "",
+			Method:   http.MethodPost,
+			Handler:  func(*gin.Context) {},
+			Request:  &createUser{},
+			Response: &apiUser{},
+		},
+		&solanum.SolaService{
+			Uri:     "/:id/files/*path",
+			Method:  http.MethodDelete,
+			Handler: func(*gin.Context) {},
+		},
+	)
+
+	m := solanum.NewModule(solanum.WithUri("/users"))
+	m.SetControllers(ctr)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(5051),
+		solanum.WithOpenAPI("/openapi.json", solanum.OpenAPIInfo{Title: "Users", Version: "1.0.0"}),
+	)
+	runner.SetModules(m)
+
+	doc := runner.OpenAPI()
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Equal(t, "Users", doc.Info.Title)
+
+	get := doc.Paths["/users/{id}"]["get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "getUser", get.OperationID)
+	assert.Equal(t, "Get a user", get.Summary)
+	assert.Equal(t, []string{"users"}, get.Tags)
+	assert.Nil(t, get.RequestBody, "a request made of parameters has no body")
+	require.Len(t, get.Parameters, 3)
+	assert.Equal(t, solanum.OpenAPIParameter{
+		Name: "id", In: "path", Required: true,
+		Schema: &solanum.OpenAPISchema{Type: "integer", Format: "int64"},
+	}, *get.Parameters[0])
+	assert.Equal(t, "verbose", get.Parameters[1].Name)
+	assert.Equal(t, "query", get.Parameters[1].In)
+	assert.False(t, get.Parameters[1].Required)
+	assert.Equal(t, "X-Tenant", get.Parameters[2].Name)
+	assert.Equal(t, "header", get.Parameters[2].In)
+	assert.True(t, get.Parameters[2].Required)
+	assert.Equal(t, "#/components/schemas/apiUser", get.Responses["200"].Content["application/json"].Schema.Ref)
+
+	post := doc.Paths["/users"]["post"]
+	require.NotNil(t, post)
+	require.NotNil(t, post.RequestBody)
+	assert.Equal(t, "#/components/schemas/createUser", post.RequestBody.Content["application/json"].Schema.Ref)
+
+	del := doc.Paths["/users/{id}/files/{path}"]["delete"]
+	require.NotNil(t, del)
+	require.Len(t, del.Parameters, 2)
+	assert.Equal(t, "path", del.Parameters[1].Name)
+	assert.Equal(t, "string", del.Parameters[1].Schema.Type)
+	assert.Nil(t, del.Responses["200"].Content)
+
+	require.NotNil(t, doc.Components)
+	user := doc.Components.Schemas["apiUser"]
+	require.NotNil(t, user)
+	assert.Equal(t, "object", user.Type)
+	assert.ElementsMatch(t, []string{"id", "name", "friends", "createdAt"}, keys(user.Properties))
+	assert.Equal(t, "#/components/schemas/apiUser", user.Properties["friends"].Items.Ref)
+	assert.Equal(t, "date-time", user.Properties["createdAt"].Format)
+
+	create := doc.Components.Schemas["createUser"]
+	require.NotNil(t, create)
+	assert.ElementsMatch(t, []string{"name", "email"}, keys(create.Properties))
+	assert.Equal(t, []string{"name"}, create.Required)
+
+	// The document is served once the modules are initialized
+	runner.InitModules()
+
+	rec := httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var served map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, "3.1.0", served["openapi"])
+	assert.Contains(t, served["paths"], "/users/{id}")
+}
+
+// keys returns the keys of a map of schemas.
+func keys(m map[string]*solanum.OpenAPISchema) []string {
+
+	ks := make([]string, 0, len(m))
+	for k := range m {
+
+		ks = append(ks, k)
+	}
+
+	return ks
+}
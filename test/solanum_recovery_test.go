@@ -0,0 +1,60 @@
+package solanum_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	solanum "github.com/annuums/solanum"
+)
+
+// TestRecoveryByDefault verifies the runner recovers from panics, logs them and
+// answers with a problem, and that WithRecovery swaps the handler.
+func TestRecoveryByDefault(t *testing.T) {
+
+	var logs bytes.Buffer
+
+	ctr := solanum.NewController()
+	ctr.SetHandlers(&solanum.SolaService{Uri: "/:id", Method: http.MethodGet, Handler: func(c *gin.Context) {
+
+		panic("nil map in orders handler")
+	}})
+	m := solanum.NewModule(solanum.WithUri("/orders"))
+	m.SetControllers(ctr)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(5052),
+		solanum.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	runner.SetModules(m)
+	runner.InitModules()
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	req.Header.Set(solanum.HeaderRequestID, "req-123")
+	rec := httptest.NewRecorder()
+	assert.NotPanics(t, func() { runner.GinEngine().ServeHTTP(rec, req) })
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, solanum.MIMEProblemJSON, rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), "nil map", "panic values are internal")
+	assert.Contains(t, logs.String(), `msg="panic recovered" panic="nil map in orders handler" method=GET route=/orders/:id request_id=req-123`)
+	assert.Contains(t, logs.String(), "goroutine", "the stack trace is logged")
+	assert.NotContains(t, logs.String(), "request failed", "the panic is logged once")
+
+	// A custom handler takes over rendering
+	solanum.WithRecovery(func(c *gin.Context, recovered any) {
+
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"message": "try again later"})
+	})(runner)
+
+	rec = httptest.NewRecorder()
+	runner.GinEngine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"message":"try again later"}`, rec.Body.String())
+}
@@ -0,0 +1,171 @@
+package solanum_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// freePort asks the kernel for a free TCP port.
+func freePort(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// newSlowModule returns a module whose /slow handler signals entry and then waits for release.
+func newSlowModule(entered chan<- struct{}, release <-chan struct{}) *solanum.SolaModule {
+	m := solanum.NewModule(solanum.WithUri("/server"))
+
+	ctrl := solanum.NewController()
+	ctrl.SetHandlers(&solanum.SolaService{
+		Uri:    "/slow",
+		Method: http.MethodGet,
+		Handler: func(c *gin.Context) {
+			entered <- struct{}{}
+			<-release
+			c.String(http.StatusOK, "drained")
+		},
+	})
+	m.SetControllers(ctrl)
+
+	return m
+}
+
+// TestStartShutdownDrainsInFlightRequests verifies Start serves in the background and
+// Shutdown waits for in-flight requests before returning.
+func TestStartShutdownDrainsInFlightRequests(t *testing.T) {
+	port := freePort(t)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	// Reset the global runner before configuring it for this test
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(port),
+		solanum.WithContainer(container.New()),
+		solanum.WithShutdownTimeout(5*time.Second),
+	)
+	runner.SetModules(newSlowModule(entered, release))
+
+	require.NoError(t, runner.Start(context.Background()))
+	assert.Error(t, runner.Start(context.Background()), "starting twice must fail")
+
+	type result struct {
+		body string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/server/slow", port))
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		done <- result{body: string(body), err: err}
+	}()
+
+	<-entered
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- runner.Shutdown(context.Background()) }()
+
+	// Shutdown must wait for the in-flight request
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("shutdown returned before the request drained: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	res := <-done
+	assert.NoError(t, res.err)
+	assert.Equal(t, "drained", res.body)
+	assert.NoError(t, <-shutdownErr)
+
+	// The listener is closed after shutdown
+	_, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/server/slow", port))
+	assert.Error(t, err)
+}
+
+// TestShutdownDrainTimeout verifies Shutdown gives up after the drain timeout.
+func TestShutdownDrainTimeout(t *testing.T) {
+	port := freePort(t)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(
+		solanum.WithPort(port),
+		solanum.WithContainer(container.New()),
+		solanum.WithShutdownTimeout(50*time.Millisecond),
+	)
+	runner.SetModules(newSlowModule(entered, release))
+	require.NoError(t, runner.Start(context.Background()))
+
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/server/slow", port))
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	err := runner.Shutdown(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestRandomPort verifies port 0 binds a random port, reported by Addr while running.
+func TestRandomPort(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	close(release)
+
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithPort(0), solanum.WithContainer(container.New()))
+	require.NotNil(t, runner.GinEngine(), "port 0 keeps a fully configured runner")
+	runner.SetModules(newSlowModule(entered, release))
+	assert.Nil(t, runner.Addr())
+
+	require.NoError(t, runner.Start(context.Background()))
+	addr, ok := runner.Addr().(*net.TCPAddr)
+	require.True(t, ok)
+	assert.NotZero(t, addr.Port)
+	assert.Zero(t, runner.Port())
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/server/slow", addr.Port))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "drained", string(body))
+
+	require.NoError(t, runner.Shutdown(context.Background()))
+	assert.Nil(t, runner.Addr())
+}
+
+// TestNoPortDefaultsToRandomPort verifies a runner without a port binds a random one.
+func TestNoPortDefaultsToRandomPort(t *testing.T) {
+	solanum.SolanumRunner = nil
+	runner := solanum.NewSolanum(solanum.WithContainer(container.New()))
+
+	require.NoError(t, runner.Start(context.Background()))
+	defer runner.Shutdown(context.Background())
+
+	assert.NotZero(t, runner.Addr().(*net.TCPAddr).Port)
+}
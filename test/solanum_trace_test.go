@@ -0,0 +1,110 @@
+package solanum_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanum "github.com/annuums/solanum"
+	"github.com/annuums/solanum/container"
+)
+
+// traceRouter serves /trace with RequestTracing, capturing what the handler sees.
+func traceRouter(t *testing.T, seen *http.Header) *gin.Engine {
+
+	r := gin.New()
+	r.Use(solanum.RequestTracing())
+	r.GET("/trace", func(c *gin.Context) {
+
+		ctx := c.Request.Context()
+
+		// Dependencies named alike neither hide nor are hidden by the trace values
+		shadowed := context.WithValue(ctx, container.NewContextKey("requestID"), "dependency")
+		shadowed = context.WithValue(shadowed, container.NewContextKey("spanContext"), "dependency")
+		assert.Equal(t, solanum.RequestID(ctx), solanum.RequestID(shadowed))
+		assert.Equal(t, solanum.SpanContext(ctx), solanum.SpanContext(shadowed))
+		assert.Equal(t, "dependency", container.DepFromContext[string](shadowed, "requestID"))
+
+		*seen = http.Header{}
+		solanum.PropagateHeaders(ctx, *seen)
+		c.Status(http.StatusNoContent)
+	})
+
+	return r
+}
+
+// TestRequestTracingContinuesTrace verifies incoming IDs and trace context are kept.
+func TestRequestTracingContinuesTrace(t *testing.T) {
+
+	var outbound http.Header
+	r := traceRouter(t, &outbound)
+
+	req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	req.Header.Set(solanum.HeaderRequestID, "req-42")
+	req.Header.Set(solanum.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(solanum.HeaderTracestate, "congo=t61rcWkgMzE")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-42", rec.Header().Get(solanum.HeaderRequestID))
+	assert.Equal(t, "congo=t61rcWkgMzE", rec.Header().Get(solanum.HeaderTracestate))
+
+	traceparent := rec.Header().Get(solanum.HeaderTraceparent)
+	require.Len(t, traceparent, 55)
+	assert.True(t, strings.HasPrefix(traceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-"))
+	assert.True(t, strings.HasSuffix(traceparent, "-01"))
+	assert.NotContains(t, traceparent, "00f067aa0ba902b7", "the request gets a span of its own")
+
+	// Outbound requests join the request and continue from its span
+	assert.Equal(t, "req-42", outbound.Get(solanum.HeaderRequestID))
+	assert.Equal(t, traceparent, outbound.Get(solanum.HeaderTraceparent))
+	assert.Equal(t, "congo=t61rcWkgMzE", outbound.Get(solanum.HeaderTracestate))
+}
+
+// TestRequestTracingStartsTrace verifies missing or malformed values are replaced.
+func TestRequestTracingStartsTrace(t *testing.T) {
+
+	var outbound http.Header
+	r := traceRouter(t, &outbound)
+
+	for _, tc := range []struct {
+		name, requestID, traceparent string
+	}{
+		{"missing", "", ""},
+		{"spaces in request ID", "req 42", "00-4bf92f3577b34da6a3ce929d0e0e4736-00000000000000000-01"},
+		{"request ID too long", strings.Repeat("a", 129), "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{"upper-case traceparent", "", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01"},
+		{"invalid version", "", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	} {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+			if tc.requestID != "" {
+
+				req.Header.Set(solanum.HeaderRequestID, tc.requestID)
+			}
+			if tc.traceparent != "" {
+
+				req.Header.Set(solanum.HeaderTraceparent, tc.traceparent)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(solanum.HeaderRequestID)
+			assert.Len(t, id, 32)
+			assert.NotEqual(t, tc.requestID, id)
+
+			traceparent := rec.Header().Get(solanum.HeaderTraceparent)
+			assert.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-00$`, traceparent)
+			assert.NotContains(t, traceparent, "4bf92f3577b34da6a3ce929d0e0e4736")
+			assert.Empty(t, rec.Header().Get(solanum.HeaderTracestate))
+		})
+	}
+}